@@ -58,7 +58,7 @@ func main() {
 
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if strings.HasPrefix(r.URL.Path, "/api/v1/stream/status") || r.URL.Path == "/health" {
+			if strings.HasPrefix(r.URL.Path, "/api/v1/stream/status") || r.URL.Path == "/health" || r.URL.Path == "/metrics" {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -69,14 +69,62 @@ func main() {
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/stream/status", handler.GetStreamStatus)
+		r.Get("/stream/health", handler.GetStreamHealth)
+		r.Get("/stream/thumbnail", handler.GetStreamThumbnail)
 		r.Get("/reservations", handler.GetReservations)
 		r.Post("/reservations", handler.CreateReservation)
+		r.Post("/slot-holds", handler.CreateSlotHold)
+		r.Post("/waitlist", handler.JoinWaitlist)
+		r.Delete("/waitlist/{entryId}", handler.LeaveWaitlist)
 		r.Delete("/reservations/{reservationId}", handler.DeleteReservation)
+		r.Post("/reservations/{reservationId}/passcode-recovery", handler.RequestPasscodeRecovery)
+		r.Put("/reservations/{reservationId}/passcode", handler.ResetPasscode)
+		r.Get("/reservations/{reservationId}/chat-messages", handler.GetChatMessages)
+		r.Get("/reservations/{reservationId}/reactions", handler.GetReactionStats)
+		r.Get("/reservations/{reservationId}/recordings", handler.GetRecordings)
+		r.Get("/reservations/{reservationId}/tracks", handler.GetTracklist)
+		r.Post("/reservations/{reservationId}/tracks", handler.SubmitTrack)
+		r.Get("/reservations/{reservationId}/handover", handler.GetHandover)
+		r.Put("/reservations/{reservationId}/consent", handler.UpdateReservationConsent)
+		r.Delete("/moderation/messages/{messageId}", handler.DeleteChatMessage)
+		r.Get("/moderation/bans", handler.GetChatBans)
+		r.Post("/moderation/bans", handler.CreateChatBan)
+		r.Delete("/moderation/bans/{banId}", handler.DeleteChatBan)
+		r.Get("/moderation/chat-settings", handler.GetChatSettings)
+		r.Put("/moderation/chat-settings", handler.UpdateChatSettings)
+		r.Get("/moderation/actions", handler.GetModerationActions)
+		r.Post("/dj-profiles", handler.CreateDJProfile)
+		r.Get("/dj-profiles/{profileId}", handler.GetDJProfile)
+		r.Put("/dj-profiles/{profileId}", handler.UpdateDJProfile)
+		r.Get("/dj-profiles/{profileId}/avatar", handler.GetDJProfileAvatar)
+		r.Put("/dj-profiles/{profileId}/avatar", handler.UploadDJProfileAvatar)
+		r.Get("/vod", handler.GetVODs)
+		r.Get("/vod/{vodId}", handler.GetVOD)
+		r.Put("/vod/{vodId}/visibility", handler.UpdateVODVisibility)
+		r.Get("/vod/{vodId}/playback", handler.PlayVOD)
 		r.Get("/available-slots", handler.GetAvailableSlots)
 		r.Get("/event-config", handler.GetEventConfig)
+		r.Get("/restream-targets", handler.GetRestreamTargets)
+		r.Post("/restream-targets", handler.CreateRestreamTarget)
+		r.Delete("/restream-targets/{targetId}", handler.DeleteRestreamTarget)
+		r.Post("/restream-targets/{targetId}/start", handler.StartRestreamTarget)
+		r.Post("/restream-targets/{targetId}/stop", handler.StopRestreamTarget)
 		r.Get("/ws/viewer", handler.HandleWebSocket)
+		r.Get("/ws/dj", handler.HandleDJWebSocket)
+		r.Get("/events/stream", handler.HandleEventStream)
 	})
 
+	// MediaMTX hooks. Not proxied by nginx, only reachable on the internal network.
+	r.Route("/internal/mediamtx", func(r chi.Router) {
+		r.Post("/segment-created", handler.RecordingSegmentCreated)
+		r.Post("/segment-completed", handler.RecordingSegmentCompleted)
+		r.Post("/ready", handler.StreamReady)
+		r.Post("/not-ready", handler.StreamNotReady)
+		r.Get("/consent", handler.GetStreamConsent)
+	})
+
+	r.Get("/metrics", handler.HandleMetrics)
+
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
@@ -106,6 +154,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Errorf("Server forced to shutdown: %v", err)
 	}
+	handler.StopRestreams()
 
 	logger.Info("Server exited")
 }
@@ -8,10 +8,15 @@ CREATE EXTENSION IF NOT EXISTS "btree_gist";
 -- Reservations table
 CREATE TABLE IF NOT EXISTS reservations (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    dj_name VARCHAR(100) NOT NULL,
+    dj_name VARCHAR(500) NOT NULL,  -- display name, B2B performers joined with " b2b "
     start_time TIMESTAMPTZ NOT NULL,
     end_time TIMESTAMPTZ NOT NULL,
     passcode VARCHAR(60) NOT NULL,  -- bcrypt hash
+    contact_email VARCHAR(254),     -- optional, used for passcode recovery
+    end_handled_at TIMESTAMPTZ,     -- set once the slot end scheduler has acted on it
+    consent_record BOOLEAN NOT NULL DEFAULT TRUE,      -- segments are discarded when FALSE
+    consent_restream BOOLEAN NOT NULL DEFAULT TRUE,    -- not forwarded to restream targets when FALSE
+    consent_public_vod BOOLEAN NOT NULL DEFAULT TRUE,  -- default vod_public of the set's stream sessions
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     
     -- Ensure no overlapping reservations
@@ -42,10 +47,202 @@ CREATE TABLE IF NOT EXISTS reservations (
 CREATE INDEX idx_reservations_start_time ON reservations(start_time);
 CREATE INDEX idx_reservations_end_time ON reservations(end_time);
 
+-- Public DJ profiles. Editing requires the profile token (only the SHA-256
+-- hash is stored); avatar_file is relative to the media directory.
+CREATE TABLE IF NOT EXISTS dj_profiles (
+    id UUID PRIMARY KEY,
+    display_name VARCHAR(100) NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    genres TEXT[] NOT NULL DEFAULT '{}',
+    links TEXT[] NOT NULL DEFAULT '{}',
+    avatar_file VARCHAR(255),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Ordered lineup of each reservation. Position 1 booked the slot and uses
+-- the reservation passcode; B2B partners have their own passcode.
+CREATE TABLE IF NOT EXISTS reservation_performers (
+    reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL CHECK (position > 0),
+    dj_name VARCHAR(100) NOT NULL,
+    passcode VARCHAR(60),  -- bcrypt hash, NULL for position 1
+    profile_id UUID REFERENCES dj_profiles(id) ON DELETE SET NULL,
+    PRIMARY KEY (reservation_id, position)
+);
+
+-- One-time passcode recovery tokens (only the SHA-256 hash is stored)
+CREATE TABLE IF NOT EXISTS passcode_recovery_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_passcode_recovery_tokens_reservation ON passcode_recovery_tokens(reservation_id);
+
+-- Live chat messages, attached to the reservation that was on air
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id UUID PRIMARY KEY,
+    reservation_id UUID REFERENCES reservations(id) ON DELETE CASCADE,
+    viewer_id VARCHAR(64) NOT NULL,
+    nickname VARCHAR(30) NOT NULL,
+    body TEXT NOT NULL,
+    ip VARCHAR(45),
+    deleted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_chat_messages_reservation ON chat_messages(reservation_id, created_at);
+
+-- Chat bans and timeouts (timeouts have an expiry)
+CREATE TABLE IF NOT EXISTS chat_bans (
+    id UUID PRIMARY KEY,
+    viewer_id VARCHAR(64),
+    ip VARCHAR(45),
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ,
+    CHECK (viewer_id IS NOT NULL OR ip IS NOT NULL)
+);
+
+-- Audit log of moderator actions
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(32) NOT NULL,
+    moderator VARCHAR(100) NOT NULL,
+    message_id UUID,
+    ban_id UUID,
+    target_viewer_id VARCHAR(64),
+    target_ip VARCHAR(45),
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_moderation_actions_created ON moderation_actions(created_at);
+
+-- Short-lived holds on a time range while a DJ fills in the booking form.
+-- Holds and reservations are written under one advisory lock so that they
+-- never overlap; expired holds are deleted by the backend.
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id UUID PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    client_ip VARCHAR(45),                             -- NULL for waitlist offers
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX idx_slot_holds_expires ON slot_holds(expires_at);
+CREATE INDEX idx_slot_holds_client_ip ON slot_holds(client_ip);
+
+-- Waitlist for booked time ranges. When a range frees up the oldest
+-- waiting entry gets a slot hold keyed by the entry's token.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY,
+    dj_name VARCHAR(100) NOT NULL,
+    contact_email VARCHAR(254),
+    webhook_url VARCHAR(2048),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting',
+    offered_at TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time),
+    CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'cancelled'))
+);
+
+CREATE INDEX idx_waitlist_entries_status ON waitlist_entries(status, created_at);
+
+-- Per-minute reaction totals for post-event stats
+CREATE TABLE IF NOT EXISTS reaction_stats (
+    reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    minute TIMESTAMPTZ NOT NULL,
+    reaction VARCHAR(32) NOT NULL,
+    count INTEGER NOT NULL,
+    PRIMARY KEY (reservation_id, minute, reaction)
+);
+
+-- Continuous publishing runs on a MediaMTX path within one reservation
+-- (rtmp_key is the path name)
+CREATE TABLE IF NOT EXISTS stream_sessions (
+    id UUID PRIMARY KEY,
+    reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    rtmp_key VARCHAR(255) NOT NULL,
+    viewer_count INTEGER NOT NULL DEFAULT 0,
+    peak_viewers INTEGER NOT NULL DEFAULT 0,
+    vod_public BOOLEAN NOT NULL DEFAULT TRUE  -- listed in /vod without DJ passcode or admin token
+);
+
+CREATE INDEX idx_stream_sessions_reservation ON stream_sessions(reservation_id, started_at);
+
+-- Recording segments written by MediaMTX. file is relative to the media
+-- directory; duration and size are set when the segment is complete.
+CREATE TABLE IF NOT EXISTS recordings (
+    id UUID PRIMARY KEY,
+    reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+    session_id UUID NOT NULL REFERENCES stream_sessions(id) ON DELETE CASCADE,
+    path_name VARCHAR(255) NOT NULL,
+    file VARCHAR(1024) NOT NULL UNIQUE,
+    started_at TIMESTAMPTZ NOT NULL,
+    duration_seconds DOUBLE PRECISION,
+    size_bytes BIGINT,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recordings_reservation ON recordings(reservation_id, started_at);
+
+-- Tracks submitted by the DJ during a set; the latest one is now playing
+CREATE TABLE IF NOT EXISTS tracks (
+    id UUID PRIMARY KEY,
+    reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    artist VARCHAR(200) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    played_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tracks_reservation ON tracks(reservation_id, played_at);
+
+-- Last time the DJ of a reservation had the DJ channel or overlay open
+CREATE TABLE IF NOT EXISTS dj_presence (
+    reservation_id UUID PRIMARY KEY REFERENCES reservations(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
+
+-- RTMP destinations the live stream is forwarded to. The replica that runs
+-- a target's forwarder claims it and renews heartbeat_at.
+CREATE TABLE IF NOT EXISTS restream_targets (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'stopped',  -- stopped, idle, running, restarting
+    restart_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    owner VARCHAR(255),
+    heartbeat_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create a view for current/next DJ info
 CREATE OR REPLACE VIEW current_next_dj AS
 WITH current_dj AS (
     SELECT 
+        id,
         dj_name,
         start_time,
         end_time
@@ -57,6 +254,7 @@ WITH current_dj AS (
 ),
 next_dj AS (
     SELECT 
+        id,
         dj_name,
         start_time,
         end_time
@@ -71,7 +269,9 @@ SELECT
     current_dj.end_time as current_end_time,
     next_dj.dj_name as next_dj_name,
     next_dj.start_time as next_start_time,
-    next_dj.end_time as next_end_time
+    next_dj.end_time as next_end_time,
+    current_dj.id as current_id,
+    next_dj.id as next_id
 FROM 
     (SELECT 1) dummy
     LEFT JOIN current_dj ON true
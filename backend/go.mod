@@ -12,6 +12,7 @@ require (
 	github.com/oapi-codegen/runtime v1.1.2
 	github.com/sirupsen/logrus v1.9.3
 	golang.org/x/crypto v0.47.0
+	golang.org/x/image v0.35.0
 )
 
 require (
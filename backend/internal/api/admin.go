@@ -0,0 +1,43 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authenticateAdmin returns the admin name for the "Authorization: Bearer
+// <token>" header if it matches a configured admin token
+func (h *Handler) authenticateAdmin(r *http.Request) (string, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return "", false
+	}
+	return h.adminName(strings.TrimSpace(token))
+}
+
+// adminName returns the name of the admin a token belongs to
+func (h *Handler) adminName(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	for candidate, name := range h.config.Admin.Admins {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// requireAdmin checks the "Authorization: Bearer <token>" header against the
+// configured admin tokens. An error response has already been written when
+// it returns false.
+func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name, ok := h.authenticateAdmin(r)
+	if !ok {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Admin token required")
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// GetChatMessages returns the chat log recorded during a reservation.
+// Only the DJ holding the reservation's passcode can read it.
+func (h *Handler) GetChatMessages(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authenticateReservation(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.db.GetChatMessages(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get chat messages: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get chat messages")
+		return
+	}
+
+	apiMessages := make([]ChatMessage, len(messages))
+	for i, msg := range messages {
+		apiMessages[i] = ChatMessage{
+			Id:       openapi_types.UUID(msg.ID),
+			Nickname: msg.Nickname,
+			Body:     msg.Body,
+			SentAt:   msg.CreatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiMessages)
+}
+
+// authenticateReservation parses the reservationId URL parameter and checks
+// the X-Reservation-Passcode header. An error response has already been
+// written when it returns false.
+func (h *Handler) authenticateReservation(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return uuid.Nil, false
+	}
+
+	if err := h.db.VerifyReservationPasscode(id, r.Header.Get("X-Reservation-Passcode")); err != nil {
+		switch err.Error() {
+		case "invalid passcode":
+			h.sendError(w, http.StatusUnauthorized, "INVALID_PASSCODE", "Invalid passcode")
+		case "reservation not found":
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Reservation not found")
+		default:
+			h.logger.Errorf("Failed to verify passcode: %v", err)
+			h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to verify passcode")
+		}
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// saveChatMessage persists accepted chat messages so DJs can read them after their set
+func (h *Handler) saveChatMessage(msg websocket.ChatMessage) {
+	err := h.db.SaveChatMessage(&db.ChatMessage{
+		ID:        msg.ID,
+		ViewerID:  msg.ViewerID,
+		Nickname:  msg.Nickname,
+		Body:      msg.Body,
+		IP:        optionalString(msg.IP),
+		CreatedAt: msg.SentAt,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to save chat message: %v", err)
+	}
+}
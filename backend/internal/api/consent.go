@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/google/uuid"
+)
+
+// streamConsent is what the internal consent endpoint returns to the
+// MediaMTX hooks. Without a reservation on air everything is allowed.
+type streamConsent struct {
+	ReservationID *uuid.UUID `json:"reservationId,omitempty"`
+	Record        bool       `json:"record"`
+	Restream      bool       `json:"restream"`
+}
+
+// UpdateReservationConsent replaces what the DJs allow for their set
+func (h *Handler) UpdateReservationConsent(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authenticateReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReservationConsent
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	files, err := h.db.UpdateReservationConsent(id, fromAPIConsent(req))
+	if err != nil {
+		if err.Error() == "reservation not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Reservation not found")
+			return
+		}
+		h.logger.Errorf("Failed to update consent: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to update consent")
+		return
+	}
+	h.logger.Infof("Consent of reservation %s changed: record=%t restream=%t publicVod=%t", id, req.Record, req.Restream, req.PublicVod)
+	for _, file := range files {
+		h.discardSegment(file)
+	}
+	h.wakeRestreams()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(req)
+}
+
+// GetStreamConsent is queried by the MediaMTX hooks with the query
+// parameter path. Recording and forwarding hooks skip their work when the
+// reservation on air opted out.
+func (h *Handler) GetStreamConsent(w http.ResponseWriter, r *http.Request) {
+	consent := streamConsent{Record: true, Restream: true}
+
+	// Only the DJ stream belongs to a reservation
+	if path := r.URL.Query().Get("path"); path == "" || path == h.config.MediaMTX.PathName {
+		reservation, err := h.db.GetReservationAt(time.Now())
+		if err != nil {
+			h.logger.Errorf("Failed to get reservation on air: %v", err)
+			h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get consent")
+			return
+		}
+		if reservation != nil {
+			consent = streamConsent{
+				ReservationID: &reservation.ID,
+				Record:        reservation.Record,
+				Restream:      reservation.Restream,
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(consent)
+}
+
+// recordingConsented reports whether the reservation on air at t allows
+// recording. Errors deny it like the recording hook does, as a recording
+// that should not exist is worse than a lost segment.
+func (h *Handler) recordingConsented(t time.Time) bool {
+	reservation, err := h.db.GetReservationAt(t)
+	if err != nil {
+		h.logger.Errorf("Failed to get reservation on air: %v", err)
+		return false
+	}
+	return reservation == nil || reservation.Record
+}
+
+// restreamConsented reports whether the reservation on air allows
+// forwarding. Errors deny it as forwarding cannot be undone.
+func (h *Handler) restreamConsented(now time.Time) bool {
+	reservation, err := h.db.GetReservationAt(now)
+	if err != nil {
+		h.logger.Warnf("Failed to get reservation on air: %v", err)
+		return false
+	}
+	return reservation == nil || reservation.Restream
+}
+
+// discardSegment removes a segment of a set whose DJs opted out of
+// recording. The recording hook usually removed new segments already.
+func (h *Handler) discardSegment(file string) {
+	err := os.Remove(filepath.Join(h.config.Media.Dir, file))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		h.logger.Warnf("Failed to discard recording %s: %v", file, err)
+		return
+	}
+	h.logger.Infof("Discarded recording %s of a set without consent to recording", file)
+}
+
+func toAPIConsent(consent db.Consent) ReservationConsent {
+	return ReservationConsent{
+		Record:    consent.Record,
+		Restream:  consent.Restream,
+		PublicVod: consent.PublicVOD,
+	}
+}
+
+func fromAPIConsent(consent ReservationConsent) db.Consent {
+	return db.Consent{
+		Record:    consent.Record,
+		Restream:  consent.Restream,
+		PublicVOD: consent.PublicVod,
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/websocket"
+)
+
+// eventStreamHeartbeat keeps proxies from closing idle event streams
+const eventStreamHeartbeat = 15 * time.Second
+
+// HandleEventStream delivers the viewer WebSocket's server messages as
+// Server-Sent Events for networks that break WebSockets. Each event's data
+// is the same JSON envelope; broadcasts carry an id for Last-Event-ID
+// resumption. Stream clients count as playing viewers.
+func (h *Handler) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Streaming is not supported")
+		return
+	}
+
+	ip := clientIP(r)
+	if !h.wsManager.AcquireIP(ip) {
+		h.logger.Warnf("Rejected event stream from %s: too many connections", ip)
+		h.sendError(w, http.StatusTooManyRequests, "TOO_MANY_CONNECTIONS", "Too many connections from this address")
+		return
+	}
+
+	viewerID, token, isNew := h.resolveViewer(r)
+	if isNew {
+		http.SetCookie(w, h.viewerCookie(token))
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter
+	// covers clients that cannot set headers
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Disable nginx response buffering for this request
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	client := websocket.NewStreamClient(h.wsManager, ip, viewerID, lastEventID)
+	client.SendMessage(websocket.TypeViewerToken, websocket.ViewerTokenPayload{Token: token})
+	h.wsManager.Register(client)
+	defer h.wsManager.Unregister(client)
+
+	// Ask EventSource to wait a few seconds before reconnecting
+	if _, err := fmt.Fprint(w, "retry: 3000\n\n"); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case frame, ok := <-client.Send:
+			if !ok {
+				// The manager dropped the client
+				return
+			}
+			if err := h.writeEvent(w, frame); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) writeEvent(w http.ResponseWriter, frame *websocket.Frame) error {
+	// Pings expect a pong, which event streams cannot send; the heartbeat
+	// comments serve the same purpose
+	if frame.Type == websocket.TypePing {
+		return nil
+	}
+
+	if id := h.wsManager.EventID(frame); id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	// Encoded envelopes never contain raw newlines
+	_, err := fmt.Fprintf(w, "data: %s\n\n", frame.Data)
+	return err
+}
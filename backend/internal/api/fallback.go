@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/mediamtx"
+)
+
+// maintainFallback keeps the fallback path configured in MediaMTX. The
+// configuration is reapplied on every poll because MediaMTX forgets API
+// changes when it restarts.
+func (h *Handler) maintainFallback() {
+	ticker := time.NewTicker(h.config.MediaMTX.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := h.ensureFallback()
+		if err == nil {
+			err = h.checkFallbackPublishing()
+		}
+		if err != nil {
+			h.logger.Warnf("Fallback stream unavailable: %v", err)
+		}
+		if ready := err == nil; h.fallbackReady.Swap(ready) != ready && ready {
+			h.logger.Infof("Fallback stream playing on %s", h.config.MediaMTX.FallbackPath)
+		}
+		<-ticker.C
+	}
+}
+
+// ensureFallback configures the fallback path to loop the fallback file and
+// redirects RTSP readers of the stream path to it. The file plays
+// continuously so that the path's ready state shows whether it works.
+func (h *Handler) ensureFallback() error {
+	cfg := h.config.MediaMTX
+	if _, err := os.Stat(filepath.Join(h.config.Media.Dir, filepath.FromSlash(cfg.FallbackFile))); err != nil {
+		return fmt.Errorf("fallback file: %w", err)
+	}
+
+	// The file is copied as is, so it must be H264/AAC for HLS
+	command := "ffmpeg -re -stream_loop -1 -i " + path.Join(cfg.MediaDir, cfg.FallbackFile) +
+		" -c copy -f rtsp rtsp://" + cfg.FallbackUser + ":" + cfg.FallbackPass + "@localhost:$RTSP_PORT/$MTX_PATH"
+	restart := true
+	// Clears the on-demand publisher of earlier versions
+	onDemand := ""
+	want := mediamtx.PathConf{RunOnInit: &command, RunOnInitRestart: &restart, RunOnDemand: &onDemand}
+
+	conf, err := h.mediamtx.GetPathConf(cfg.FallbackPath)
+	switch {
+	case errors.Is(err, mediamtx.ErrNotFound):
+		if err := h.mediamtx.AddPathConf(cfg.FallbackPath, want); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case conf.RunOnInit == nil || *conf.RunOnInit != command || conf.RunOnInitRestart == nil || !*conf.RunOnInitRestart ||
+		(conf.RunOnDemand != nil && *conf.RunOnDemand != ""):
+		if err := h.mediamtx.PatchPathConf(cfg.FallbackPath, want); err != nil {
+			return err
+		}
+	}
+
+	fallback := "/" + cfg.FallbackPath
+	conf, err = h.mediamtx.GetPathConf(cfg.PathName)
+	if err != nil {
+		return err
+	}
+	if conf.Fallback == nil || *conf.Fallback != fallback {
+		return h.mediamtx.PatchPathConf(cfg.PathName, mediamtx.PathConf{Fallback: &fallback})
+	}
+	return nil
+}
+
+// checkFallbackPublishing fails unless the fallback ffmpeg is publishing
+func (h *Handler) checkFallbackPublishing() error {
+	fallback, err := h.mediamtx.GetPath(h.config.MediaMTX.FallbackPath)
+	if errors.Is(err, mediamtx.ErrNotFound) || (err == nil && !fallback.Ready) {
+		return fmt.Errorf("%s has no publisher yet", h.config.MediaMTX.FallbackPath)
+	}
+	return err
+}
+
+// fallbackActive reports whether the fallback stream should play instead of
+// an offline player
+func (h *Handler) fallbackActive(now time.Time) bool {
+	if !h.fallbackReady.Load() {
+		return false
+	}
+	if h.config.EventStartTime != nil && now.Before(*h.config.EventStartTime) {
+		return false
+	}
+	if h.config.EventEndTime != nil && now.After(*h.config.EventEndTime) {
+		return false
+	}
+	return true
+}
+
+// hlsPlaylist returns the HLS playlist path of a MediaMTX path behind nginx
+func hlsPlaylist(pathName string) string {
+	return "/hls/" + pathName + "/index.m3u8"
+}
@@ -17,39 +17,234 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	AdminTokenScopes     = "AdminToken.Scopes"
+	ModeratorTokenScopes = "ModeratorToken.Scopes"
+)
+
 // Defines values for ErrorCode.
 const (
-	BEFOREEVENTSTART    ErrorCode = "BEFORE_EVENT_START"
-	DBERROR             ErrorCode = "DB_ERROR"
-	DURATIONTOOLONG     ErrorCode = "DURATION_TOO_LONG"
-	EXCEEDSEVENTEND     ErrorCode = "EXCEEDS_EVENT_END"
-	INVALIDDJNAME       ErrorCode = "INVALID_DJ_NAME"
-	INVALIDPASSCODE     ErrorCode = "INVALID_PASSCODE"
-	INVALIDREQUEST      ErrorCode = "INVALID_REQUEST"
-	INVALIDTIMEINTERVAL ErrorCode = "INVALID_TIME_INTERVAL"
-	INVALIDTIMERANGE    ErrorCode = "INVALID_TIME_RANGE"
-	NOTFOUND            ErrorCode = "NOT_FOUND"
-	OUTSIDEEVENTBOUNDS  ErrorCode = "OUTSIDE_EVENT_BOUNDS"
-	PASTTIME            ErrorCode = "PAST_TIME"
-	RANGETOOLARGE       ErrorCode = "RANGE_TOO_LARGE"
-	TIMECONFLICT        ErrorCode = "TIME_CONFLICT"
+	BEFOREEVENTSTART      ErrorCode = "BEFORE_EVENT_START"
+	DBERROR               ErrorCode = "DB_ERROR"
+	DURATIONTOOLONG       ErrorCode = "DURATION_TOO_LONG"
+	EXCEEDSEVENTEND       ErrorCode = "EXCEEDS_EVENT_END"
+	HEALTHUNAVAILABLE     ErrorCode = "HEALTH_UNAVAILABLE"
+	INTERNALERROR         ErrorCode = "INTERNAL_ERROR"
+	INVALIDAVATAR         ErrorCode = "INVALID_AVATAR"
+	INVALIDDJNAME         ErrorCode = "INVALID_DJ_NAME"
+	INVALIDEMAIL          ErrorCode = "INVALID_EMAIL"
+	INVALIDHOLD           ErrorCode = "INVALID_HOLD"
+	INVALIDPASSCODE       ErrorCode = "INVALID_PASSCODE"
+	INVALIDPROFILE        ErrorCode = "INVALID_PROFILE"
+	INVALIDPROFILETOKEN   ErrorCode = "INVALID_PROFILE_TOKEN"
+	INVALIDRANGE          ErrorCode = "INVALID_RANGE"
+	INVALIDRECOVERYTOKEN  ErrorCode = "INVALID_RECOVERY_TOKEN"
+	INVALIDREQUEST        ErrorCode = "INVALID_REQUEST"
+	INVALIDRESTREAMTARGET ErrorCode = "INVALID_RESTREAM_TARGET"
+	INVALIDSIGNATURE      ErrorCode = "INVALID_SIGNATURE"
+	INVALIDTIMEINTERVAL   ErrorCode = "INVALID_TIME_INTERVAL"
+	INVALIDTIMERANGE      ErrorCode = "INVALID_TIME_RANGE"
+	INVALIDTRACK          ErrorCode = "INVALID_TRACK"
+	INVALIDWEBHOOKURL     ErrorCode = "INVALID_WEBHOOK_URL"
+	NOTFOUND              ErrorCode = "NOT_FOUND"
+	OUTSIDEEVENTBOUNDS    ErrorCode = "OUTSIDE_EVENT_BOUNDS"
+	PASTTIME              ErrorCode = "PAST_TIME"
+	RANGETOOLARGE         ErrorCode = "RANGE_TOO_LARGE"
+	RECOVERYUNAVAILABLE   ErrorCode = "RECOVERY_UNAVAILABLE"
+	SLOTHELD              ErrorCode = "SLOT_HELD"
+	TIMECONFLICT          ErrorCode = "TIME_CONFLICT"
+	TOOMANYCONNECTIONS    ErrorCode = "TOO_MANY_CONNECTIONS"
+	TOOMANYPERFORMERS     ErrorCode = "TOO_MANY_PERFORMERS"
+	UNAUTHORIZED          ErrorCode = "UNAUTHORIZED"
+	VODUNAVAILABLE        ErrorCode = "VOD_UNAVAILABLE"
+)
+
+// Defines values for HandoverStatusState.
+const (
+	Ended    HandoverStatusState = "ended"
+	OnAir    HandoverStatusState = "on_air"
+	Upcoming HandoverStatusState = "upcoming"
+)
+
+// Defines values for ModerationActionAction.
+const (
+	ModerationActionActionBan           ModerationActionAction = "ban"
+	ModerationActionActionChatSettings  ModerationActionAction = "chat_settings"
+	ModerationActionActionDeleteMessage ModerationActionAction = "delete_message"
+	ModerationActionActionTimeout       ModerationActionAction = "timeout"
+	ModerationActionActionUnban         ModerationActionAction = "unban"
+)
+
+// Defines values for PasscodePolicyCharset.
+const (
+	Alphanumeric PasscodePolicyCharset = "alphanumeric"
+	Any          PasscodePolicyCharset = "any"
+	Numeric      PasscodePolicyCharset = "numeric"
+)
+
+// Defines values for RecordingStatus.
+const (
+	RecordingStatusComplete  RecordingStatus = "complete"
+	RecordingStatusRecording RecordingStatus = "recording"
 )
 
+// Defines values for RestreamTargetStatus.
+const (
+	Idle       RestreamTargetStatus = "idle"
+	Restarting RestreamTargetStatus = "restarting"
+	Running    RestreamTargetStatus = "running"
+	Stopped    RestreamTargetStatus = "stopped"
+)
+
+// Defines values for StreamStatusState.
+const (
+	Fallback StreamStatusState = "fallback"
+	Live     StreamStatusState = "live"
+	Offline  StreamStatusState = "offline"
+)
+
+// Defines values for TimeSlotState.
+const (
+	Available TimeSlotState = "available"
+	Held      TimeSlotState = "held"
+	Reserved  TimeSlotState = "reserved"
+)
+
+// ChatBan defines model for ChatBan.
+type ChatBan struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// CreatedBy Moderator who created the ban
+	CreatedBy string `json:"createdBy"`
+
+	// ExpiresAt End of a timeout. Omitted for permanent bans.
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Ip Banned IP address
+	Ip     *string `json:"ip,omitempty"`
+	Reason string  `json:"reason"`
+
+	// ViewerId Banned viewer identity
+	ViewerId *string `json:"viewerId,omitempty"`
+}
+
+// ChatMessage defines model for ChatMessage.
+type ChatMessage struct {
+	Body     string             `json:"body"`
+	Id       openapi_types.UUID `json:"id"`
+	Nickname string             `json:"nickname"`
+	SentAt   time.Time          `json:"sentAt"`
+}
+
+// ChatSettings defines model for ChatSettings.
+type ChatSettings struct {
+	// SlowModeSeconds Minimum seconds between messages from one viewer (0 = off)
+	SlowModeSeconds int `json:"slowModeSeconds"`
+
+	// SubscriberOnly Only viewer identities older than the configured minimum age may chat
+	SubscriberOnly bool `json:"subscriberOnly"`
+}
+
+// CreateChatBanRequest defines model for CreateChatBanRequest.
+type CreateChatBanRequest struct {
+	// DurationSeconds Timeout length, at most one year. 0 or omitted creates a permanent ban.
+	DurationSeconds *int `json:"durationSeconds,omitempty"`
+
+	// IncludeIp With messageId, also ban the IP address the message was sent from
+	IncludeIp *bool `json:"includeIp,omitempty"`
+
+	// Ip Ban this IPv4 or IPv6 address (ignored when messageId is set)
+	Ip *string `json:"ip,omitempty"`
+
+	// MessageId Ban the author of this message
+	MessageId *openapi_types.UUID `json:"messageId,omitempty"`
+	Reason    *string             `json:"reason,omitempty"`
+
+	// ViewerId Ban this viewer identity, a UUID (ignored when messageId is set)
+	ViewerId *string `json:"viewerId,omitempty"`
+}
+
 // CreateReservationRequest defines model for CreateReservationRequest.
 type CreateReservationRequest struct {
+	// AdditionalPerformers B2B partners playing after djName, in order. Each can manage the reservation with their own passcode.
+	AdditionalPerformers *[]PerformerInput `json:"additionalPerformers,omitempty"`
+
+	// Consent What the DJs allow for their set. Everything is allowed when a reservation is created without it.
+	Consent *ReservationConsent `json:"consent,omitempty"`
+
+	// ContactEmail Optional contact email used to send a passcode recovery link. Never returned by the API.
+	ContactEmail *openapi_types.Email `json:"contactEmail,omitempty"`
+
 	// DjName DJ display name (emojis allowed)
 	DjName string `json:"djName"`
 
 	// EndTime Must be on 15-minute intervals, max 1 hour from start
 	EndTime time.Time `json:"endTime"`
 
-	// Passcode 4-digit passcode for deletion
+	// HoldToken Token of a slot hold covering the requested range. Without it, held ranges are rejected with SLOT_HELD.
+	HoldToken *string `json:"holdToken,omitempty"`
+
+	// Passcode Passcode for deletion. Length and allowed characters follow the passcode policy in EventConfig (4 digits by default).
 	Passcode string `json:"passcode"`
 
+	// ProfileToken Token of the booking DJ's profile, attaching the profile to the reservation
+	ProfileToken *string `json:"profileToken,omitempty"`
+
+	// StartTime Must be on 15-minute intervals
+	StartTime time.Time `json:"startTime"`
+}
+
+// CreateRestreamTargetRequest defines model for CreateRestreamTargetRequest.
+type CreateRestreamTargetRequest struct {
+	// Enabled Start forwarding right away
+	Enabled *bool  `json:"enabled,omitempty"`
+	Name    string `json:"name"`
+
+	// Url rtmp://, rtmps:// or srt:// destination
+	Url string `json:"url"`
+}
+
+// CreateSlotHoldRequest defines model for CreateSlotHoldRequest.
+type CreateSlotHoldRequest struct {
+	// EndTime Must be on 15-minute intervals, max 1 hour from start
+	EndTime time.Time `json:"endTime"`
+
 	// StartTime Must be on 15-minute intervals
 	StartTime time.Time `json:"startTime"`
 }
 
+// CreatedDjProfile defines model for CreatedDjProfile.
+type CreatedDjProfile struct {
+	Profile DjProfile `json:"profile"`
+
+	// Token Secret for editing the profile and attaching it to reservations. Only returned once.
+	Token string `json:"token"`
+}
+
+// DjProfile defines model for DjProfile.
+type DjProfile struct {
+	// AvatarUrl Absolute URL of the avatar image (absent if none was uploaded)
+	AvatarUrl   *string            `json:"avatarUrl,omitempty"`
+	Bio         string             `json:"bio"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	DisplayName string             `json:"displayName"`
+	Genres      []string           `json:"genres"`
+	Id          openapi_types.UUID `json:"id"`
+
+	// Links Social links (http/https URLs)
+	Links     []string  `json:"links"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DjProfileInput defines model for DjProfileInput.
+type DjProfileInput struct {
+	Bio         *string   `json:"bio,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Genres      *[]string `json:"genres,omitempty"`
+	Links       *[]string `json:"links,omitempty"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Code    ErrorCode `json:"code"`
@@ -65,60 +260,454 @@ type EventConfig struct {
 	EventEndTime *time.Time `json:"eventEndTime,omitempty"`
 
 	// EventStartTime Event start time (can be null if not configured)
-	EventStartTime *time.Time `json:"eventStartTime,omitempty"`
+	EventStartTime *time.Time     `json:"eventStartTime,omitempty"`
+	PasscodePolicy PasscodePolicy `json:"passcodePolicy"`
+
+	// PasscodeRecoveryEnabled Whether recovery links can be sent to a reservation's contact email
+	PasscodeRecoveryEnabled bool `json:"passcodeRecoveryEnabled"`
 
 	// Timezone IANA timezone identifier for the event (e.g., "Asia/Tokyo")
 	Timezone string `json:"timezone"`
 }
 
+// HandoverNextDj The reservation after this one; unset once the slot ended
+type HandoverNextDj struct {
+	DjName string `json:"djName"`
+
+	// Ready Whether the next DJ has the DJ channel or overlay open
+	Ready         bool               `json:"ready"`
+	ReservationId openapi_types.UUID `json:"reservationId"`
+	StartTime     time.Time          `json:"startTime"`
+}
+
+// HandoverStatus defines model for HandoverStatus.
+type HandoverStatus struct {
+	DjName  string    `json:"djName"`
+	EndTime time.Time `json:"endTime"`
+
+	// NextDj The reservation after this one; unset once the slot ended
+	NextDj        *HandoverNextDj    `json:"nextDj,omitempty"`
+	ReservationId openapi_types.UUID `json:"reservationId"`
+
+	// SecondsLeft Seconds until the slot ends (0 once it ended)
+	SecondsLeft int `json:"secondsLeft"`
+
+	// SecondsUntilStart Seconds until the slot starts (0 once it started)
+	SecondsUntilStart int                 `json:"secondsUntilStart"`
+	StartTime         time.Time           `json:"startTime"`
+	State             HandoverStatusState `json:"state"`
+}
+
+// HandoverStatusState defines model for HandoverStatus.State.
+type HandoverStatusState string
+
+// JoinWaitlistRequest defines model for JoinWaitlistRequest.
+type JoinWaitlistRequest struct {
+	// ContactEmail Optional address notified when the range frees up. Never returned by the API.
+	ContactEmail *openapi_types.Email `json:"contactEmail,omitempty"`
+	DjName       string               `json:"djName"`
+
+	// EndTime Must be on 15-minute intervals, max 1 hour from start
+	EndTime time.Time `json:"endTime"`
+
+	// StartTime Must be on 15-minute intervals
+	StartTime time.Time `json:"startTime"`
+
+	// WebhookUrl Optional http(s) URL that receives a POST when the range frees up. Only accepted when WAITLIST_WEBHOOKS_ENABLED is set. The host must resolve to a public address; redirects are not followed.
+	WebhookUrl *string `json:"webhookUrl,omitempty"`
+}
+
+// ModerationAction defines model for ModerationAction.
+type ModerationAction struct {
+	Action    ModerationActionAction `json:"action"`
+	BanId     *openapi_types.UUID    `json:"banId,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+
+	// Details Additional information such as the ban reason or new chat settings
+	Details        *string             `json:"details,omitempty"`
+	Id             openapi_types.UUID  `json:"id"`
+	MessageId      *openapi_types.UUID `json:"messageId,omitempty"`
+	Moderator      string              `json:"moderator"`
+	TargetIp       *string             `json:"targetIp,omitempty"`
+	TargetViewerId *string             `json:"targetViewerId,omitempty"`
+}
+
+// ModerationActionAction defines model for ModerationAction.Action.
+type ModerationActionAction string
+
+// PasscodePolicy defines model for PasscodePolicy.
+type PasscodePolicy struct {
+	// Charset Characters allowed in passcodes
+	Charset   PasscodePolicyCharset `json:"charset"`
+	MaxLength int                   `json:"maxLength"`
+	MinLength int                   `json:"minLength"`
+}
+
+// PasscodePolicyCharset Characters allowed in passcodes
+type PasscodePolicyCharset string
+
+// Performer defines model for Performer.
+type Performer struct {
+	DjName string `json:"djName"`
+
+	// Position Playing order, 1 is the DJ who booked the slot
+	Position int        `json:"position"`
+	Profile  *DjProfile `json:"profile,omitempty"`
+}
+
+// PerformerInput defines model for PerformerInput.
+type PerformerInput struct {
+	DjName string `json:"djName"`
+
+	// Passcode This performer's own passcode, accepted wherever the reservation passcode is. Follows the passcode policy.
+	Passcode string `json:"passcode"`
+
+	// ProfileToken Token of this performer's DJ profile, attaching the profile to the reservation
+	ProfileToken *string `json:"profileToken,omitempty"`
+}
+
+// ReactionMinute defines model for ReactionMinute.
+type ReactionMinute struct {
+	// Counts Count per reaction name (e.g. fire, heart)
+	Counts map[string]int `json:"counts"`
+
+	// Minute Start of the minute
+	Minute time.Time `json:"minute"`
+	Total  int       `json:"total"`
+}
+
+// Recording defines model for Recording.
+type Recording struct {
+	// DurationSeconds Only set once the segment is complete
+	DurationSeconds *float64 `json:"durationSeconds,omitempty"`
+
+	// EndedAt Only set once the segment is complete
+	EndedAt *time.Time         `json:"endedAt,omitempty"`
+	Id      openapi_types.UUID `json:"id"`
+
+	// SessionId Stream session the segment belongs to
+	SessionId openapi_types.UUID `json:"sessionId"`
+
+	// SizeBytes File size, only set once the segment is complete
+	SizeBytes *int64          `json:"sizeBytes,omitempty"`
+	StartedAt time.Time       `json:"startedAt"`
+	Status    RecordingStatus `json:"status"`
+}
+
+// RecordingStatus defines model for Recording.Status.
+type RecordingStatus string
+
 // Reservation defines model for Reservation.
 type Reservation struct {
-	CreatedAt time.Time `json:"createdAt"`
+	// Consent What the DJs allow for their set. Everything is allowed when a reservation is created without it.
+	Consent   ReservationConsent `json:"consent"`
+	CreatedAt time.Time          `json:"createdAt"`
 
-	// DjName DJ display name (emojis allowed)
-	DjName    string             `json:"djName"`
+	// DjName DJ display name (emojis allowed). B2B sets combine all performers as "A b2b B".
+	DjName  string             `json:"djName"`
+	EndTime time.Time          `json:"endTime"`
+	Id      openapi_types.UUID `json:"id"`
+
+	// Performers Lineup in playing order
+	Performers []Performer `json:"performers"`
+	StartTime  time.Time   `json:"startTime"`
+}
+
+// ReservationConsent What the DJs allow for their set. Everything is allowed when a reservation is created without it.
+type ReservationConsent struct {
+	// PublicVod Recordings of the set are listed in the public archive by default
+	PublicVod bool `json:"publicVod"`
+
+	// Record The set may be recorded
+	Record bool `json:"record"`
+
+	// Restream The set may be forwarded to the restream targets
+	Restream bool `json:"restream"`
+}
+
+// ResetPasscodeRequest defines model for ResetPasscodeRequest.
+type ResetPasscodeRequest struct {
+	// NewPasscode New passcode, subject to the passcode policy
+	NewPasscode string `json:"newPasscode"`
+
+	// Token One-time token from the recovery link
+	Token string `json:"token"`
+}
+
+// RestreamTarget defines model for RestreamTarget.
+type RestreamTarget struct {
+	// Enabled Whether the target was started by an admin
+	Enabled bool               `json:"enabled"`
+	Id      openapi_types.UUID `json:"id"`
+
+	// LastError Last error output of the forwarder
+	LastError *string `json:"lastError,omitempty"`
+	Name      string  `json:"name"`
+
+	// Replica Backend replica running the forwarder
+	Replica *string `json:"replica,omitempty"`
+
+	// RestartCount Forwarder restarts since the target was last started
+	RestartCount int `json:"restartCount"`
+
+	// Status stopped: not enabled. idle: enabled, waiting for a DJ to go live.
+	// running: forwarding, reported once ffmpeg has run for five
+	// seconds. restarting: the forwarder exited (or could not connect)
+	// and is restarted after a backoff of up to one minute.
+	Status    RestreamTargetStatus `json:"status"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+
+	// Url Destination including the stream key
+	Url string `json:"url"`
+}
+
+// RestreamTargetStatus stopped: not enabled. idle: enabled, waiting for a DJ to go live.
+// running: forwarding, reported once ffmpeg has run for five
+// seconds. restarting: the forwarder exited (or could not connect)
+// and is restarted after a backoff of up to one minute.
+type RestreamTargetStatus string
+
+// SlotHold defines model for SlotHold.
+type SlotHold struct {
 	EndTime   time.Time          `json:"endTime"`
+	ExpiresAt time.Time          `json:"expiresAt"`
 	Id        openapi_types.UUID `json:"id"`
 	StartTime time.Time          `json:"startTime"`
+
+	// Token Secret to pass as holdToken when creating the reservation. Only returned once.
+	Token string `json:"token"`
+}
+
+// StreamHealth defines model for StreamHealth.
+type StreamHealth struct {
+	AudioCodec *string `json:"audioCodec,omitempty"`
+
+	// BitrateKbps Inbound bitrate since the previous poll
+	BitrateKbps *float32 `json:"bitrateKbps,omitempty"`
+
+	// FramesInError Frames dropped because of errors since the publisher connected
+	FramesInError *int64 `json:"framesInError,omitempty"`
+
+	// Height Video height, if MediaMTX reports it
+	Height *int `json:"height,omitempty"`
+
+	// History One sample per poll, oldest first
+	History []StreamHealthSample `json:"history"`
+
+	// Live Whether a publisher is connected to the stream path
+	Live bool `json:"live"`
+
+	// PacketsLost Packets lost by the publishing connection (RTSP and SRT only)
+	PacketsLost    *int64     `json:"packetsLost,omitempty"`
+	PublisherSince *time.Time `json:"publisherSince,omitempty"`
+
+	// PublisherType MediaMTX source type of the publisher, e.g. rtmpConn
+	PublisherType *string `json:"publisherType,omitempty"`
+
+	// Reconnects Reconnections of the publisher within the reconnect window
+	Reconnects int `json:"reconnects"`
+
+	// UpdatedAt Last successful poll of the MediaMTX API; unset before the first one
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+
+	// UptimeSeconds How long the current publisher has been connected
+	UptimeSeconds *int    `json:"uptimeSeconds,omitempty"`
+	VideoCodec    *string `json:"videoCodec,omitempty"`
+
+	// Width Video width, if MediaMTX reports it
+	Width *int `json:"width,omitempty"`
+}
+
+// StreamHealthSample defines model for StreamHealthSample.
+type StreamHealthSample struct {
+	At time.Time `json:"at"`
+
+	// BitrateKbps Unset for the first poll of a publisher
+	BitrateKbps *float32 `json:"bitrateKbps,omitempty"`
+
+	// FramesInError Frames dropped since the previous sample
+	FramesInError int64 `json:"framesInError"`
+	Live          bool  `json:"live"`
+
+	// PacketsLost Packets lost since the previous sample
+	PacketsLost int64 `json:"packetsLost"`
 }
 
 // StreamStatus defines model for StreamStatus.
 type StreamStatus struct {
-	// CurrentDj Name of current DJ (null if no one is scheduled)
+	// Connections Number of open viewer WebSocket connections, including multiple tabs of the same viewer
+	Connections *int `json:"connections,omitempty"`
+
+	// CurrentDj Name of current DJ (null if no one is scheduled). B2B sets are rendered as "A b2b B".
 	CurrentDj *string `json:"currentDj,omitempty"`
 
 	// CurrentEndTime End time of current session
 	CurrentEndTime *time.Time `json:"currentEndTime,omitempty"`
 
+	// CurrentPerformers Lineup of the current reservation in playing order
+	CurrentPerformers *[]Performer `json:"currentPerformers,omitempty"`
+
 	// CurrentStartTime Start time of current session
 	CurrentStartTime *time.Time `json:"currentStartTime,omitempty"`
+	CurrentTrack     *Track     `json:"currentTrack,omitempty"`
 
-	// IsLive Whether stream is currently live
+	// ExternalViewers Viewers reading the stream directly from MediaMTX (RTSP/RTMP/WebRTC). Only present when the MediaMTX API is configured.
+	ExternalViewers *int `json:"externalViewers,omitempty"`
+
+	// IsLive Whether a DJ is currently live (false while the fallback stream plays)
 	IsLive bool `json:"isLive"`
 
 	// NextDj Name of next DJ
 	NextDj *string `json:"nextDj,omitempty"`
 
+	// NextPerformers Lineup of the next reservation in playing order
+	NextPerformers *[]Performer `json:"nextPerformers,omitempty"`
+
 	// NextStartTime Start time of next session
 	NextStartTime *time.Time `json:"nextStartTime,omitempty"`
 
-	// ViewerCount Number of current viewers
+	// PlaybackPath HLS playlist to play in the live and fallback states
+	PlaybackPath *string `json:"playbackPath,omitempty"`
+
+	// State live: a DJ is publishing. fallback: no DJ is publishing and the
+	// "be right back" loop plays instead (requires FALLBACK_FILE and the
+	// MediaMTX API, only within the event period). offline: nothing plays.
+	State StreamStatusState `json:"state"`
+
+	// UniqueViewers Distinct anonymous viewer identities whose player is running
+	UniqueViewers *int `json:"uniqueViewers,omitempty"`
+
+	// ViewerCount Number of current viewers (unique viewers plus external viewers)
 	ViewerCount *int `json:"viewerCount,omitempty"`
 }
 
+// StreamStatusState live: a DJ is publishing. fallback: no DJ is publishing and the
+// "be right back" loop plays instead (requires FALLBACK_FILE and the
+// MediaMTX API, only within the event period). offline: nothing plays.
+type StreamStatusState string
+
+// SubmitTrackRequest defines model for SubmitTrackRequest.
+type SubmitTrackRequest struct {
+	Artist string `json:"artist"`
+
+	// PlayedAt When the track started, within the reservation (default now)
+	PlayedAt *time.Time `json:"playedAt,omitempty"`
+	Title    string     `json:"title"`
+}
+
 // TimeSlot defines model for TimeSlot.
 type TimeSlot struct {
 	Available bool      `json:"available"`
 	EndTime   time.Time `json:"endTime"`
 	StartTime time.Time `json:"startTime"`
+
+	// State Why the slot is (un)available. Held slots free up when the hold expires.
+	State TimeSlotState `json:"state"`
+}
+
+// TimeSlotState Why the slot is (un)available. Held slots free up when the hold expires.
+type TimeSlotState string
+
+// Track defines model for Track.
+type Track struct {
+	Artist        string             `json:"artist"`
+	Id            openapi_types.UUID `json:"id"`
+	PlayedAt      time.Time          `json:"playedAt"`
+	ReservationId openapi_types.UUID `json:"reservationId"`
+	Title         string             `json:"title"`
+}
+
+// UpdateVodVisibilityRequest defines model for UpdateVodVisibilityRequest.
+type UpdateVodVisibilityRequest struct {
+	Public bool `json:"public"`
+}
+
+// Vod A finished stream session of a reservation
+type Vod struct {
+	DjName          string    `json:"djName"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	EndedAt         time.Time `json:"endedAt"`
+
+	// Id Stream session ID
+	Id         openapi_types.UUID `json:"id"`
+	Performers []Performer        `json:"performers"`
+
+	// PlaybackUrl Signed fragmented MP4 URL; unset when VOD playback is not configured
+	PlaybackUrl          *string    `json:"playbackUrl,omitempty"`
+	PlaybackUrlExpiresAt *time.Time `json:"playbackUrlExpiresAt,omitempty"`
+
+	// Public Whether the set is listed publicly
+	Public        bool               `json:"public"`
+	ReservationId openapi_types.UUID `json:"reservationId"`
+	StartedAt     time.Time          `json:"startedAt"`
+}
+
+// WaitlistEntry defines model for WaitlistEntry.
+type WaitlistEntry struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	DjName    string             `json:"djName"`
+	EndTime   time.Time          `json:"endTime"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Position Position among waiting entries that overlap the same time (1 = next)
+	Position  int       `json:"position"`
+	StartTime time.Time `json:"startTime"`
+
+	// Token Secret used as holdToken when the range is offered and to leave the waitlist. Only returned once.
+	Token string `json:"token"`
 }
 
+// OptionalReservationPasscode defines model for OptionalReservationPasscode.
+type OptionalReservationPasscode = string
+
+// ProfileId defines model for ProfileId.
+type ProfileId = openapi_types.UUID
+
+// ProfileToken defines model for ProfileToken.
+type ProfileToken = string
+
+// ReservationPasscode defines model for ReservationPasscode.
+type ReservationPasscode = string
+
+// RestreamTargetId defines model for RestreamTargetId.
+type RestreamTargetId = openapi_types.UUID
+
+// VodId defines model for VodId.
+type VodId = openapi_types.UUID
+
 // GetAvailableSlotsParams defines parameters for GetAvailableSlots.
 type GetAvailableSlotsParams struct {
 	StartTime time.Time  `form:"startTime" json:"startTime"`
 	EndTime   *time.Time `form:"endTime,omitempty" json:"endTime,omitempty"`
 }
 
+// UpdateDjProfileParams defines parameters for UpdateDjProfile.
+type UpdateDjProfileParams struct {
+	// XProfileToken Token returned when the profile was created
+	XProfileToken ProfileToken `json:"X-Profile-Token"`
+}
+
+// UploadDjProfileAvatarParams defines parameters for UploadDjProfileAvatar.
+type UploadDjProfileAvatarParams struct {
+	// XProfileToken Token returned when the profile was created
+	XProfileToken ProfileToken `json:"X-Profile-Token"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// LastEventId Alternative to the Last-Event-ID header
+	LastEventId *string `form:"lastEventId,omitempty" json:"lastEventId,omitempty"`
+
+	// ViewerToken Anonymous viewer token when the dsr_viewer cookie is not available
+	ViewerToken *string `form:"viewerToken,omitempty" json:"viewerToken,omitempty"`
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetModerationActionsParams defines parameters for GetModerationActions.
+type GetModerationActionsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // GetReservationsParams defines parameters for GetReservations.
 type GetReservationsParams struct {
 	Date *string `form:"date,omitempty" json:"date,omitempty"`
@@ -126,47 +715,284 @@ type GetReservationsParams struct {
 
 // DeleteReservationJSONBody defines parameters for DeleteReservation.
 type DeleteReservationJSONBody struct {
-	// Passcode 4-digit passcode
+	// Passcode Passcode set when the reservation was created
 	Passcode string `json:"passcode"`
 }
 
+// GetChatMessagesParams defines parameters for GetChatMessages.
+type GetChatMessagesParams struct {
+	// XReservationPasscode Passcode of the reservation
+	XReservationPasscode ReservationPasscode `json:"X-Reservation-Passcode"`
+}
+
+// UpdateReservationConsentParams defines parameters for UpdateReservationConsent.
+type UpdateReservationConsentParams struct {
+	// XReservationPasscode Passcode of the reservation
+	XReservationPasscode ReservationPasscode `json:"X-Reservation-Passcode"`
+}
+
+// GetHandoverParams defines parameters for GetHandover.
+type GetHandoverParams struct {
+	// XReservationPasscode Passcode of the reservation
+	XReservationPasscode ReservationPasscode `json:"X-Reservation-Passcode"`
+}
+
+// SubmitTrackParams defines parameters for SubmitTrack.
+type SubmitTrackParams struct {
+	// XReservationPasscode Passcode of the reservation
+	XReservationPasscode ReservationPasscode `json:"X-Reservation-Passcode"`
+}
+
+// GetVodsParams defines parameters for GetVods.
+type GetVodsParams struct {
+	// Dj Part of the DJ name, ignoring case
+	Dj *string `form:"dj,omitempty" json:"dj,omitempty"`
+
+	// ProfileId Only sets a DJ profile performed in
+	ProfileId *openapi_types.UUID `form:"profileId,omitempty" json:"profileId,omitempty"`
+
+	// Date Only sets that started on this day in the event timezone
+	Date *openapi_types.Date `form:"date,omitempty" json:"date,omitempty"`
+}
+
+// GetVodParams defines parameters for GetVod.
+type GetVodParams struct {
+	// XReservationPasscode Passcode of the reservation, instead of an admin token
+	XReservationPasscode *OptionalReservationPasscode `json:"X-Reservation-Passcode,omitempty"`
+}
+
+// PlayVodParams defines parameters for PlayVod.
+type PlayVodParams struct {
+	Expires   int64   `form:"expires" json:"expires"`
+	Signature string  `form:"signature" json:"signature"`
+	Range     *string `json:"Range,omitempty"`
+}
+
+// UpdateVodVisibilityParams defines parameters for UpdateVodVisibility.
+type UpdateVodVisibilityParams struct {
+	// XReservationPasscode Passcode of the reservation, instead of an admin token
+	XReservationPasscode *OptionalReservationPasscode `json:"X-Reservation-Passcode,omitempty"`
+}
+
+// LeaveWaitlistParams defines parameters for LeaveWaitlist.
+type LeaveWaitlistParams struct {
+	// XWaitlistToken Token returned when joining the waitlist
+	XWaitlistToken string `json:"X-Waitlist-Token"`
+}
+
+// CreateDjProfileJSONRequestBody defines body for CreateDjProfile for application/json ContentType.
+type CreateDjProfileJSONRequestBody = DjProfileInput
+
+// UpdateDjProfileJSONRequestBody defines body for UpdateDjProfile for application/json ContentType.
+type UpdateDjProfileJSONRequestBody = DjProfileInput
+
+// CreateChatBanJSONRequestBody defines body for CreateChatBan for application/json ContentType.
+type CreateChatBanJSONRequestBody = CreateChatBanRequest
+
+// UpdateChatSettingsJSONRequestBody defines body for UpdateChatSettings for application/json ContentType.
+type UpdateChatSettingsJSONRequestBody = ChatSettings
+
 // CreateReservationJSONRequestBody defines body for CreateReservation for application/json ContentType.
 type CreateReservationJSONRequestBody = CreateReservationRequest
 
 // DeleteReservationJSONRequestBody defines body for DeleteReservation for application/json ContentType.
 type DeleteReservationJSONRequestBody DeleteReservationJSONBody
 
+// UpdateReservationConsentJSONRequestBody defines body for UpdateReservationConsent for application/json ContentType.
+type UpdateReservationConsentJSONRequestBody = ReservationConsent
+
+// ResetPasscodeJSONRequestBody defines body for ResetPasscode for application/json ContentType.
+type ResetPasscodeJSONRequestBody = ResetPasscodeRequest
+
+// SubmitTrackJSONRequestBody defines body for SubmitTrack for application/json ContentType.
+type SubmitTrackJSONRequestBody = SubmitTrackRequest
+
+// CreateRestreamTargetJSONRequestBody defines body for CreateRestreamTarget for application/json ContentType.
+type CreateRestreamTargetJSONRequestBody = CreateRestreamTargetRequest
+
+// CreateSlotHoldJSONRequestBody defines body for CreateSlotHold for application/json ContentType.
+type CreateSlotHoldJSONRequestBody = CreateSlotHoldRequest
+
+// UpdateVodVisibilityJSONRequestBody defines body for UpdateVodVisibility for application/json ContentType.
+type UpdateVodVisibilityJSONRequestBody = UpdateVodVisibilityRequest
+
+// JoinWaitlistJSONRequestBody defines body for JoinWaitlist for application/json ContentType.
+type JoinWaitlistJSONRequestBody = JoinWaitlistRequest
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RY73PivBH+V3bU9wM34wS4pn2vfHMwuSGTF1JMrp25N2UUewHltSWfJJPQDP97R5KN",
-	"TTCEu0vbTwn6sbt69nlWK7+QSKSZ4Mi1Ir0XoqIlptT+25dINU5QoVxRzQSf4LcclTZzmRQZSs3Qrowf",
-	"RzRF+x+qSLLMrCY9ElxDzFSW0DVwmiK0MBWPTAFNEvGE8QfikZQ+3yBf6CXpdTsdj6SMb397RK8zJD2i",
-	"tGR8QTYeQR5PWZOr33Kl4QFBcOj+5SxlPNcIjGsTe6I8SOkzdGEpcglzKVJQmkpNPDIXMqWa9EhMNZ5p",
-	"Y7vBbUaVikTc4PfiLGYLpqFcAXMhIcYE7bzZqTVKs/JfXztnf7t/udj80uTBxvMjRzvxDBuPSPyWM4kx",
-	"6X0tM1b3W4FbO+/91pB4eMRIm1AHUgq5T4ISH+R5alxMh78NZv3x6Opm2J8Sj9z64XRmBolHhqMv/s0w",
-	"sD9nw9F0MPni3xCPBHcTfzocj2bT8Xh2Mx59rq299cOwPw72tk/80WczaP+6jf7Ejgz+2R8MgnA2+DIY",
-	"TWeDUUA8cjm4Gk8GxVA49SfTmr3gejbybYDju2k4DMp1l+O7URDWFk4Gf78bhGbraDydXZlpE/3lbDCZ",
-	"jCc11KoEp6gUXViEjqfG4litb8zACrnuCz5ni/08oJkcHNKJ3QrIYzA8gVZEueEWz5ME2By40BBZw7l0",
-	"Aj1NINZpeJjDzq0l2/s6NuP/FrzB5dAf+VBOA4uRazZnKK1C9RLBxgwtPF+ce/A78RWj7an4Yy1+J8Y/",
-	"PtM0S4y3auZNXW3DaUpbrZQ2yMdW29i35fW0s79j2T1WaE8LhsU7a/OcxW/WuR8oXNbqG9WrgrIpC6GW",
-	"SNNQU52rhjTkUiLXweM+rMYniDkUSyC4hlZFX7AsU2Au0DhPHMx7xy/2HpZnKcyaH4VKucvktEwU+46o",
-	"Max0+BN+mLphqwbr/1iiXqIEZYE2mBQekjUkZsfW1oMQCVJujHF8Pgq6mYfguikOM3XyYa2d7z3piuET",
-	"yr7IuW6IME8fUNaBdMtVZclc1guU+2R2CDax1JwlTERDs0VXlCX0IalfJTUgv1u4P6vIZg1WUe6fzlhg",
-	"fC72sfRvh7Y8B9dFdXYcYnwBtFCGNkYhpZwuMEVuejjNtK3TwTW4eybc7grXSmMK/u2QeGSFUjk/3fPO",
-	"ecccXmTIacZIj/zZDtmGbWlxbm+PcKYS4brjBdqEmHTYMj6MSY98Ru2XS0O70liRNEVtWND7+kKYcfot",
-	"R7kmHuG2aO/gVuGpZY5e0YUf4rKY2/vL2gNJ+QKhNQzH8OmvnS7cTfvgsvjh5NawOcAqmYfCMdXq1GDO",
-	"IcA5zROtQAv49aPtxVWtGTe+yk4gk2LFYozPTz3CvcFQZYIrJ5KPnY7rSblGJ1qaZQmLbNbaj8rdwNW5",
-	"mMbUbvxF4pz0yJ/a1bOoXbyJ2ltNbrYBUCnp2jH6FZNLRrjKYxkET0wvGbd4qQwj047EDjNj8uI7Yz4W",
-	"quvRG+Ia8hVNWHHJuHQJCfgcIcYKfv14Zp9ICUuZtkpXeZpSuXY0B3rkVLRm02SILgz3TVrKpkeRe2Oy",
-	"baV9Fm3710Oqqre5P5nfo1jV3DQg5kpK2Zu67m0fGdxfBYxHSR6bMuQaX1PCys5b1SAqkHDg7OB1BJxJ",
-	"fd1JBcfo54iYr1iiUcLWELSqa9toMlcYw8MaHmj0B/LYM9I16McwtzvNOQVP1h/+X/Kst9cnKPSGKVtK",
-	"64DXFVq9RorkZiiZiKFVezvaF6ipZ9snph350CSdJDnoqm7/sHQ8kgnVQIa97zTFhYJKX4p4/W46Ofg9",
-	"aLPbEpgrbLOX8O67xbGT5/281qaheAn8z6trAT+0bEk0TEpYpD3IqHKtqAeoo/PXPHEIAwWOT3WyHC+n",
-	"9aH2S+3XMN44mSeocZ83gR3f5U1TGTEdUVVFduwfbV3eeAu6ovBjNN3tiE//OnfyB7lXPe6R72GncP9i",
-	"P7I6S12CCpZ2G75kFKzaRmEXvmHUlOy5yHn8imMu60BP55frwdtq+2I+dCXtvKz/ixf2jp8GFfbLB23x",
-	"AOWOic0Xd7S7WJXRl4C4cQOF2YpyVWojlwnpkaXWWa/dTkREk6VQuvep86nTphlrr7pkc7/5TwAAAP//",
-	"85n/y2AXAAA=",
+	"H4sIAAAAAAAC/+x9a3PbOLbgX0Fpt6rtLVlyHp07m675oFhKorQteSXZ6bvjlA2JkIUOBXAAyIomlf9+",
+	"6+BBgiQo0bGtdM/0l+5YJPE4OC+c59fGjC8TzghTsvH6ayPBAi+JIkL/NUwU5QzHIyKJuMPwxzmWcsYj",
+	"Ao8jImeC6ncarxvuCeJzpBYEieyjJqJMKoIjeIYZwtGSMqT4Z8IazQaFrxcER0Q0mg2Gl6TxuvHbkTfp",
+	"UTprsyFnC7LEML3aJPCmVIKy28a3b83GueBzGpN+BI/1sAlWi2zQJH3ebAjyzxUVJGq8VmJF/HHnXCyx",
+	"arxurFYU3qycZ6I3UAKE/hkJolaCkQitF4RpgNjZ0RpLNBMEKxJV795OcTSxQKpebnl5Dz2ue5/JPRen",
+	"BMHLCRa3RFUelXKPH3ZSlzyqnOKORw8c/5t7WVPLyQKrN1gjRCJ4QoSiRD+wh91RuTEjrMiRoktSHrjp",
+	"PnmzKR/cGY+IwIoLtF5wh0j6CKeYhcYiXxIqiOyo8lg9ZkgSwTr4SrXQcEkVjDfnAiVELDEjTMHIstVo",
+	"1lw8jWoAr9mgSXlBbzADmumfIxxFgkgZ+lAQLDkLoFezcUfJmoh+VDmyeQHRiDBF1SaINBlC/KNBo0Y6",
+	"oX8sTe9UP6WD8OnvZKZgIYAMZ0RKfEvKCDHl0Sa4/JqQY3T22SDx18YSfzkl7FYtGq9fHAfelYSp+pgX",
+	"2n06W9MsPB2zat9johRlt7K8cRnzNeDvmMw4i2QAuSmjy9USSfMCmhK1JoShpQGlRHPBl4gz4g7y4Bj9",
+	"HfH5/LDRbCzNx43XGRwoU+SWCA2I1RRmmhIxZHGAruDXAnpQIhGPIyKQWmDDxGeczentSpAI2ekQviVo",
+	"iTdotsAqg+iU85hgVgJpEQSlhQWhqnHNMpgR+eeKSFWGbrQSmjVXQndiqBzFGmGaCCu05FJpeG4IFi10",
+	"jLhA3PIAg+ES4TwnAEawxF8MqF88+/nFq+Pj413Qp2wWryLSD9D8R6oW7oD7URPhWHI0tfDOOIH+076m",
+	"ZShgocaHANAruQtSCypR//zuJey0f373Kh3/gN4yLpzETheEKEylDhtNn9Ze/hygtfSbqqkJwiu14MII",
+	"XSrdLI3mbqLPuJ63jJ+PQzS/lQuaiQt8sIkwurjod+8HhFcvQwykAns93aESg3EUUaNynhMBELGKaGET",
+	"z9+gBAvFiJAoifGGsluE54oIFP0+wEsC+ibiIiKihXp4tkAzzNASM8Ccgq6D1oB9akGoQHzNUGK1GkBy",
+	"qshST/+/BZk3Xjf+VztTlttW8LfThfZZstLbXeIvffPlixQYWAi8gYczzgBvd43qAevEfmE+VnimektM",
+	"4wADs9o6sq8hAu+hlQT1gAO5RAinG0SCzPgdERsUU/a5hQbkjohMa51uNKQ65/2c3NdD5pHg+c8vAyho",
+	"DqK8yO4HFFEJh4ZApqADsuS/U4lwHPM1iQoI9swylvTvwEyERRMamupsJRWaEsQZevbz0ZKylSKIMgWA",
+	"jWUTLfEX9Awt+EoYqSIVFqq2krPgcbT1AqD1KhlzheBVpIENiGoQUBMAiZDA7Ja0EPBA4MxUNdGCxPZ3",
+	"ibCAl4GQSGRQdXw6nFy/7512W6FVJbv1fdDsIhIT+L2FDGQRZpE7ApBjAs8UENecw2/m9uK+T3hMZxug",
+	"sN4dYepEC0R08BJF9JYqCZgTkTlexeowvMQalyd7J5ly/hlA1v3wk3TXJ5BaCs8WDpT2Z8Dw8jWmNLk+",
+	"4+9Bl5p4URD2lgr8eTOE9U7r0zau6V2XKhknYXgaE8vwNfAbr+c4lqRZ2OUYFgI4sMYiAhgKertQCK/x",
+	"JihGA2rms6DMWYkASxJqmbxut5sI/iFft9sgdKVQ8K+ISEVZxUkV4GjVT5ijGlTjmKv3PI62AOmHcIo9",
+	"41wI1aqBFnV/t6aGMryS7ME2WZWNAMI/TNZjMhNE4x0iIOILxKvZT0rWVAE1e5QsW0hr56l04mxGWjsh",
+	"4dbvVhWCwpbt4zussLgI4XVnKnkMZ3UxOnXMyryO6BK0jAM81eopnSPGmdFXV0nMcWRkXAlJppSXyey4",
+	"2jJwH2OCFbiDuqR8S5gwIEg1oNI7Rb2m5s0VdI2ARjfmM4pjrYlIdLBQKmnDfyTAVx76qtjOhayS6H7w",
+	"CV15fYiZs0mh4vbgn4Q/61YkMzpi2R5Q9/B3nOQOTSlwrgXrwY4BMs322XEZ8unZpqNXEA0c7YE8hMNt",
+	"NHffZrJZfy5OWhS3HnhC59ATgosy+J3CRBhcX//RmPTPetcnw8Hb0/7JpNFsnHfGk2v4sdFs9AeXndN+",
+	"V/953R9MeqPLDuyiezHqTPrDwfVkOLw+HQ7eee+ed8bjk2G39PmoM3gHP+r/mw87I/1L77eTXq87vu5d",
+	"9gaT696g22g23vTeDkc9+9N40hlNvPG6H64HHb3A4cVk3O+6994MLwbdsffiqPf/Lnpj+HQwnFy/hcew",
+	"+jfXvdFoOPJe7J11+qe5D0+Gl73Rf19Phr/2BrBq98PFoHPZ6Z923pyaHU56o0HnNB0Q9nXWGfw3QHTQ",
+	"OwEgwYIuBp2LyfvhqP//e7CCVK31pnw/zP35sffm/XD467VBm3TY897o7XB01hv52zwfDd/2T3vlX9Ll",
+	"u987l51JB9Z5OeyWtmJeGfffDTqTi5H/mzu79EBHnZNfG83G+17ndPK+YqBRbzwZ9Tpn1xM450njUwjb",
+	"M6PhdkZlbe/u/SC+Zwp6QBuCh70qlUh/iuDKCFwTHcANekoQW8WxEWrKM4Ud1taF9KTjaoXITKtVmMed",
+	"2Kna5/r2svNSn3/b+35kb809X+HOWbMWRC30Tdq7Xktkt6GVAsUR9tWbn2T+yh5UxGFj/+IsALN+Z9BB",
+	"7rG16MwpEVrbAs1EAx0dkNZtq4muGh1JcXvCP2/4VQMASL7gZQIKkPdkp5hMl1MCbTWsQij6HrMIXhqQ",
+	"L6r7e+A+WDDXGDOPNmFxRn5BKyaJ0vqg3qq+bxMWaddawTqaWiRCprVoU32UMDAjXxTqfkALbAyR3Q9w",
+	"T2aMxNpiekcEmDR4Qljw9Lwt9OtpSrlbw3foMfkZm+GrqNn4tnMZK6xWAUv+Fmh616x6tMnSs99GkwVM",
+	"+T6gGsv4KZmr4B0FHqIVUzTOYZMEN4PGMWqxy9PhfReDGeECBtBMrvYk+lBy0+hfKie6L3LoT1ROy1kl",
+	"M76Eh80GZ9eYCnNfzNHpQ3ArM3OYqfPQDwErhIcfOGUfMVUxldXWj5p2UWfoZ1wzSM81r61taC4IgWva",
+	"ExpD/xy2zae2WDQbazJdcP75Qmw7L++eAA44BTKV0DvtkTofjifV56etBXg2I4lyx/yx05+c9scTp0aO",
+	"r3sDUM+61rXRQiBqFlwqtIQ9CiJ5fEeMsE5W05jOHAL9ggSJqCAzZQy0oJMYSymJWgV8OH75twcZCUM0",
+	"YSMAKGedmYFZyXSR/u6oXVt8yXXmb7LBAsYj2Gg2Vsz8Al7Ma+k8uCENdYrrstvvsVUQhWkcuj2mriFE",
+	"mRkL9AG5mi2QlcngMzReMpDJjKy1Sxalm/n+UIWcY2/32y5CI2ywMJEtyZaHl57/roa1wp62P/Gu8ITz",
+	"kjpc4KgLLCQJyK+TzDng/AU085rJRjPFOLZaEkFnsL44WWDvT7YJ33wysvkakHkeqyw/LoAle9cftplu",
+	"KwgS58jbpuzstJ0lXFJHewUHjHVUar9kEz1D1GBt94MO5AFfB4lSjcAPZ3gWUgHub5wtWkfdSlP5tBUq",
+	"Faar7xZt1Z6qCSj3iZv3J5nzyzZzbF1oOV106bp3EZUt9FYzZhnyYT3MPVVYZPfDY3ioqkTDVk/RiBgO",
+	"cKalcUg9WjFV9K/n3iijV4HqYQTYLhJ2Lue9bd220JwKAm5LLNRhI7C+ZbqukC/Kms/tS3UVCMUVjutx",
+	"AjOsBYL7MgzHGddOse8IrdEaR/4qSm6X2gUAt3u4YBd2x1fT2NsaWy2nBvRaCe+ox5jjEaL0JJEyvWAV",
+	"z08QvET2jdyCpiTm7FYixeuEtkj6L/Jmo0gArm+BcuB5E/H7bp8y9epl9f3pfoqJTC/CTsCJFF2ajXT6",
+	"T7X8CxlQ/bWkk4SRM2MboQvQA6JKvkNL+87ojhaC2B1JlD6wKWUEHmV8VIIqd9XooOnzKXpz1WjV8Q/c",
+	"295QE/OTLSFIp5SRVaJVH1+s3ztoKOTBeqjlh+au5N4uqu7nDnt2KY0B9AnYzLCyao09dmeGpMJcs3pg",
+	"ElTG0ZspkfqOlrOKwlO7HrROw2NaJbueuZtd8gCDShm6dFIG2Adc2WKqo2+ojcy31zsxW9A74kWwVNjy",
+	"YNSwqRLGh1jQqYmvEsYQGRrDhHXsHMVGapgQLqs+6C+RuSrI3RGndr3epE0PaFUHrc5TK26F1YWRdXV6",
+	"wYCsPZ1NrvTYbg8FPSws34P615AZIjDJI8a2YaDiWdt3G69tUoW/gwo4eME3O6JuqizH5pxMxKph9YBg",
+	"LgsmiB11nelYqtSlWeBPWCpE4BniK5WsUjXL4ZMIDciqDeRJTGc4FE86+0xYhOwLSKwYc3rv1qkAF7FQ",
+	"WrEMSH33KbLvSSSpk/oeQAECDqpVgt5K7fwEUvEkIdFrbbuxx9hCNIrJa/dnE62xiVQBBoZBwVcc3XIU",
+	"0zvSumJ2q6+9YKomwIHrI9Y6yny+TMit9hmIFdPjzOkduWLW9tly29Pj5GCGyBcK4xxwgWZ8FUfO88XI",
+	"TB1eMcx0TK79nkTWMYLRFM8+8/kcznuVwIo5c8p164p593MLggagm1ZE7YYa6eHAH6GL+r1DLCqiw7pZ",
+	"BBgy8eEOdSyL+0w2Of+UDSfTB2B/bc34so2TpG0+OTKf1MlqyELKmikhpwhTQNBdAR4u9GxrzFk9SOWy",
+	"dR5Xk7+/02B7NJfimpODzpZGwxpBruV2FuyayvTvi+My27E8O6zCZFALHo/GjfcEx2pRPiK8iig/4RGZ",
+	"wV8Ztp2d994dvUQdeBwO2VICK/LrNAkwmD6b8hWLkH3JY1+JIHeUryQIvzh0/5sLvCSyzypY+1v9GEVC",
+	"ky+akhleSZ3Gp/m9zym1lJcgiCzj0Che4360IBAVWp77kkaEI/O0Ce74MxJRfDb5zTI+iagKD0il4iKY",
+	"dUOQ1BDX5gWASVPn3EgFdgWp6qrU/hmPzRF+C8Uo3ZFqcY09iFGZAc3pLZYt2TTCstxOQBoqecqlCgVg",
+	"64co5lI5j5KdDijFTga88GA0GZ/rmMjxaKKvvYf1ji1d/RhQoD6Zp99N9JOSz8cdsuQrAai1SdK00fTT",
+	"JtK2IGDRJ5yxsMy3m5RhVd0BQJYG1xcAq6yno6A1ZRFfByGRE1EB1UiuZjMi5XwVa5RzE6Y77Zz3XWzB",
+	"lMy5MPSkERKZqIeaci+BJ5VWo/d8jcBQokefrYQgTHm7BsVhSoCfeuRb3uwdUGWAgb1//uplaFFrGqlF",
+	"eTGGuPXDe9B2gVdrAssddUb8u1izJdsyg76HLNzKlC/0gbqwGHOa7vg92n84Uw5we8Pk6tGx41IP4TAP",
+	"WkLhUHVeoz3ZPCDyK6o+4KowEo/oA3dIDX04HJ4Q5lLWPpLpmMOcHsuUTU+BXK5iRUGgKDzNLv5gjjIj",
+	"BEFuiS8UggSGFBjG0Wf3AzrIwtG0fk0lAjkUreK8kcuk77CICBIFTFtlh6mZojoqz8XjecuxtsTaLMl+",
+	"d77bumVB5ybK2WaewvBlJ9oSHjjOAgMfDoGJwLPPuxZsXtJ6uSKC4dj4ZmWIfeoHSBBcvMeYYIF4Y+wV",
+	"KV/Vcr49mpydtz+S6Whycmj14wRAzVQW3+DLJauZ2AjIVhCbqTzdoeh0P+hxDCTijb7UogOdMoTWC+2u",
+	"AhaJ4xgulKnmE+ONPAwnCpEvW6nHBtBVxX/VR0c90JPjIsxSGxH1ku6LhbBmgO05Dgnj96djvauYSnPR",
+	"Apu61YD0WYF66B0PVsb5ngr/9iKW7lJMWJRwylSbsoh8aS1frP62NUQsvxSY7nWKMpnK2krnBzNK6bFe",
+	"oVqQK3bVAHOozjODt68aKOY8MciUVoY5sAJHored09M3nZNfryFeOxvFpwHrDPK0QhPjmhBBOXBgPp/H",
+	"lBFYmDE168nyBhAn0OwmGs2G/Sps9GD0nytSSf1dKhVlM4Uw42yzBHFbriWwXnBJ9FLMBSOzuISUOvi6",
+	"wj6WSUbHA+8s9zkw60z/TuKVRI53uV8Pd4t8y0EcUgQl+2q6pIaLVid0C0XN77mgqOMKeghr7B8dG1TC",
+	"IrtQ2j7nXwoyfnBgjfeI8XX9AHFFVUxqLLSoGpkNuu9DcAIOAgaiYIYZjcHyFFb27m07ekhYaBHkmyw4",
+	"lWq0OkxX20LvIUUZnkkddAeWxlRW6Wxna49peeSWbdZF7urrDGQ77/aahq0+/pDVeJrK+SrU/G4HoYex",
+	"9aB9/4jlFC1rlanJh+UWcNNbcAhMF/rKfMmjSyrplMZUbSrJ2riPQlhbWJd9MTRf0GPXQXPK4BIWOZXD",
+	"RRfoK1o+dqZ2RHogeuN+cRj3scNujZHod+vEQ+Q9zw9XZpyqEQy3HdNbBgWfBNbBFCRCZ+cvIebWGUA0",
+	"ZV8Ou8gNAwwhn4ezTb+5EHHv/jbtDMOqPWuwOCqdL9d8EW8eM//ioXmklYHyZa+8jQBxSFdG2uY2YnIh",
+	"8j2mxOZRKpA9ZoJHXX5aHTtpnyC8BGuZc8wRpgQl0gSHmxycJLvtm/yxZ+jvWj9/tESK7T4RXW2l7BDJ",
+	"gtQpmCTmxiDAtGU5JvjOXLfW9hQfwU2yq/JEFvy5LebD5LSsBFWbMTAag02daElZRYCkfqZjNKW0TvqM",
+	"TcAFptM96w9MFubYlXPUxEqw8G0zC6USL9idi4oJ0+fbJj0bdnujzmQ4qj0xbB3izAM7PO9rE2L3g710",
+	"GEGV3njokijQSWzBoSVhmQjWEVImx3GcfjXeSEWWcK1pNBt3REgzz7PWcesYIMATwnBCG68bL/RPTV3H",
+	"UJ9EO9WAjrQ2Br/ZSAUg/5TfNd4R1XGvjvWbzVytz3/YCon/XBGxyUok5nPFqsokbonq1ONZzD/oj4fo",
+	"b6+On6GLyQkyxHZYu8ZFeIEZRlctx5Y4rLWYFuqaqwNgEfqv5zqBRnoZNDCXy0JNBAfDe1S3LuK3T1oe",
+	"JJxJQ0bPj49d8pINpcKJjqKAlbd/t8W+sn3V0gPSy0ZJDfhWiupNMcKwSqPPe5cqmZCZSZTSMIMhX95z",
+	"zduWaizIgXX12R2OqbV0muPiEBAxIySS6L+eH8GxoJguqWVRq+USi41Bc4S37Ap7Y8IJ4VtZyGeTjU8w",
+	"ZDv6/cjGbRuFN2hwH2kGLQtR3rdGQ4EpEUbS+co/E2ZyjPQ/tQ5FiAnsumIkoqpYEaUJcuTGj0e/aep0",
+	"JB1dbquk5HRiY9/IU74p85KlARhCJlK9sUUoH+UsCxUuvn37VmQY30rY/+zRZi+VsgkglX2UVt7dNzK7",
+	"g51TEkeygLdmA8bG5lWtseiZImIJNdtf05LG37bxfv/4C2w/tKnslXZWUvnB/KtumkoJhJOMLsyxvXz6",
+	"Y3PoYnL7ViwKcZqdxwU6e5BvJDGeEcM4FBiPDVakfm8zgmEXrq6QRJ9JouNf88dr7u2PcsLNui8bXezb",
+	"pz8OM9kTKtpHyMYX/HAuAtM/2//05pbxR6FFQwEP455tQ2YeEy1e78BeaYQ6RGwcgfcgQjOb52VKpctf",
+	"0E1aNexGF4i41WZ/wnSiGtZJqaZCGM0KgpVp2mfZHbOwJ2Xceknt/5M/q1SpnVKGxSZc/7zMqf06aD8O",
+	"RXQGMHeLcZDewcR/kvnV34enT7KyngjKZLv0ToHdiR98OO+9a6Lzwbsmetd/Cyv8SKbnh1kdZlOL6Pqs",
+	"89v1r29CnB528YiI8aj8/vtx6A/C2w08kVRc7JGzDzjgoFwlNnDc4kq+PNXhfy6nbzZePnuxh31rsLs7",
+	"Zo4QA4dRED9AlvdnJCCNtAXpaJaW6KpS4P1KXk9IH/40ASDpx6lpTU8Q4Kik/JYXnWVqe4GlzBUXkx54",
+	"zFc+cFwQQ6Vk7sTaua3oXZpiXYoUA3sdI2rNxWdrLZ7GfPY5ewNCA3Rimln9TxJFWGHHw7U5+cN4OECE",
+	"3ZGYJwSu5jblNZvlQBKCcELbWG7YDCe0tcHL+LB1xd4IjqOZzsnRe0IzLIROAqLRL1lEK8BnFlP9hi7X",
+	"TRVMBBGrRxr2R/2uNhfYQihZ5INe5wYtqZSQyNIx2dhTguEgli5JVgcVEb3NZz+7HgutKzZMCLNmTIl0",
+	"ojRMa6AoQ0YFY7zUS6qyJBabx+Q2sb2PT40TzoMknStkItRZUvBmP7rntMVIDpW350dSXNsnMyhUTZxf",
+	"zHdLh1ZkPnKdfapXtFtvg7uj5SEZlVQPWEHQ9lNgtc//79Oz2gnnYBvf+PGbLpOPyrTxS56xWD+qhbfF",
+	"eSyR1svF0Rj2keKj4yZ2Y4abLNOqOW2cRZxWMdxijZ2aBnNjlMxbo205blMUw/XN+DnXMiNQ4WM/5uLi",
+	"NuuYjbNvEHbRt4yss4yNfekqZ1RKE/WHqFVb0go4TnHxPFj60IrupH98+vbJxzMQYEDcS2+Tq4gqFPNb",
+	"D7Oyx2XsgmZJ21DLtnGRjX0csJ2sljtgpjksLL+QgvNnP1BsdqarQcH2UkcdX+X4Re5UmxVGfzi6QisX",
+	"7BqlNJFOFS00V0Ftv42NCwZuXbEOMn7YO4IK3n6v945dZ66FXqEhT6XZ353+09jpgl2J9m36d/hdxibo",
+	"dbNva//Adi4yKcq39M5dnv4g1LOne5ztfFa02N2HbuH0uDD+OkB/7GVv1GTC7a+6VN03Q8O6PEuJH3f1",
+	"7xmdhGR8vm2gHvNBbQPLkv1luFNTTOcp8v5nIRDs/gHIc0rnSufCpzhkihzWwRyQEkfSa2G3TY6nre6e",
+	"8FKemycAqxMbAq7F21JX4ft30MBm5W1VS+pV4IiMW6B0Sk8gB0sHtD+L5i7kMECISsixR59VSkt/Zqw8",
+	"0b4ciCZZ68G0Cpl1bjzS+ShLU5K/DpdxDS3bX9PaogU5VXQZL/kdyTdBTKvfxKl2y+fWvmNVUFjlgkZE",
+	"Iqqy942J23wR89uypyGTimdpwdjdkjHdydNLR6dgGHBFf+lY3yUmzTEjbLlDetSVCJwLlNoiGkf+e7UM",
+	"J5EtEV4RxfeWxooIlA6EDrLURcZt2O10o/PLCIuagOsA/AjN9Zf6zGwhhR8Sl+dBpM5d/JRKTcw+wP3Q",
+	"PC+81c98QwdeexjdZAYMpmkXGf3LYcgJGseVU/njV8fMZbfl0D10lMvbeLq7aKDH6J7vo7lzLp+r9/iH",
+	"RaI5L/WBvlsBJsV0ppoowdKktDYRUbPWYVWMGsQwFIrYVsdR+j+1v+byIWrcy/J4s1sCFfMtHiiFvg9N",
+	"C6lSuztypkk2pd60OC2BuLvZXXUhuzoU8DKkcmQrKcvZCrexW0UmBasHrYrnScXSo2CZudA5fWvXhe7M",
+	"vbdfdNsdieEB7jwF8qd9mZAtWOqIrhNPl5AuZV0n5hsf5ZoI0wdp/zENZeR82onrILu77mY6fMxv0+Kh",
+	"YBfWmSUVtAAf1KKBrF5rMHoJem6AirSyWoetm2rpXrp0u59k9sxY0SMqZ1hE8orNV0LHvduizOBDKAya",
+	"JcpIxRPpd6FNS05cMZvVB44XKpp6kvxAtlorZCSaq026uCvmrS697eSru4aM5eaSHChv++dhAo+vUQXA",
+	"sWfbRtUKirXp9aMfFpIrnKL5FzdLzSTrBU4rGEe6/61LhlEPEOQL2wGsMhRo4mJ1/A4xhW5tBzfttWxH",
+	"v980UThc54olK7kgsqmjhoZvxmgq+FoScWTL39lmbzaQCOqHtdA5j2PNp2MJwQ3is7xidmJsSgJt0rpj",
+	"3Q9eTblliB+9I8p1O/vP0EPq9H2zJcQCiOneQLZ461+U6OkVjmhMXFcEvVQCpRQcSbq365Cjf7MJGuBz",
+	"ZcT/PPe3XSKpVBn9ey9Ybpy9iy6/ODuKODFhaxIrKuebYIX2fZGUaxmaJUlaM2vTlpTRgf04NhwVrH7F",
+	"ODGirIki3cJKOg3aH/sBcsiNfORGrM4Q7cRrkBQGFyJjV0PPj5+b+366Iiq9tFXJjWSZ4TgmQreOZVxd",
+	"sUTwKYhWV8LNW5WuFIrzXWSRILdUKgJF4gIyxiLveaFX648g0xydPA/Xhk2L+yPXV79oLAmA4Afogtl6",
+	"+l2Y/ufjPQSvp4zER6h8hRYTraxjgMUdESWqYRDAzl13haQ0oAZ9uYNVqHXxd1KV6yy1w7FgXgJlQP5w",
+	"XH0SP0Gul1cNe4v7AunGVropma33n4/iayEzpky7qaQQ13VK+ZIqBclofzQtJiHCNRoVhb3WNZNkuFUL",
+	"EZ0NofKi4ZVGhnelX+yTstQE0kI9yNT32lRlPPmK4VtMmVQlRmYsH0ZCZI2AtSHEfUKVtNNdMVtaqmmZ",
+	"H2UrXVw+q8MoVqyiVF7F3SNrm/PvSWF2d/WIy77sm7VKobF/MJVflBa9TeX30L0GcegSjFtZtC63F5vC",
+	"c/92yJOWA96FOPrFSvO3KcT3h0Qe5Y5vG85YLKgOku5EkY5dhvec2pCObOoa2JQB84rUtURNV09XfFgS",
+	"dcVSSzA0uuBLa+11sWHmY1fi2/BfYwRApkEONMphfMVmpomDq4pqzVE3jK+vbe3gGy+Ry3ovgplPWdHT",
+	"/2jrcKD465497ZYUK0gPwu33eKN2cUGm5KYJOlWxyQdwVTfBfyFpRMoFa11+6WTUOfn18C/71SYlNMdD",
+	"QrzHyipT4tp1w6vS2Po6AdWyj6zLlscsbMMnm/8IgXSgu5lxW2E1ye8Ot590nvyctbJ64thu4g+U1aNb",
+	"31WFYvrlBcuB3ZnCXGqDmF08Xd7fzqgkH5hPHJjkTfXjYpNyyBNgnfrJnnmnY0O6YywXUHo2Y4mj3ngy",
+	"6nXOried0bve5PBPibWdKDKqlI+yYYwNMbX2V/OPHSHCfSU9zkYlsh3+suJ3c7JOs74rYn5LNHG/4iL5",
+	"z8PFZwKWcIt3Qgc5R3+YM96TOLS73xLIuw25TGT4I+FXW8vAanv22yxkQ6xYrrSR6f+gezMi7SaRKaNO",
+	"xWoZ63S10D0g3fE+uegidem4s/gLo++B0Ron/OggV+fyMdCbJ9XYPVmQh3DQseLJX6j8FyrnUJkn34/J",
+	"MubqCCqJb6lBe24qSWIkF1woW5YOvtF2E6/eba5rh2lMZktOYXnFVixzQGrriO6ngW6gPccNZFbcFGtN",
+	"39jYPEh8whB5V4psNwMZIWArmd/ow7vRlW3TEuk34OIEZ66OmVmiFVM0LvUSQQfj0+Hk+v3wtHs97p0M",
+	"B91xE72wrg7pNW2HgjsAFeMQAFewAYcWUyzzIzPiNaBvIVv+QIMb4HPFdDEPEmmPiinLA3n83jKgPtR5",
+	"b3TdP2/q4bw1aNhQdsW0pwIBGG1B+vznZ/3BxaQ3Tod5deyP0gRnML5ixhVsPcFmO2ZnwoZbgc1MKhrH",
+	"+qEmG0aq6wKk/Yqf8s7jJvlBt510j6GIIYCgrsEa/ciq1gc6WE2sYiJNmFhmeLcXnX2U4/FbE7jICteg",
+	"Bx1AytD1yXDw9rR/MjkEbqxxebpBki8JYD2JZYrWvdPu4f4KCYElWFPGT2nZoHQHlo6lKTVkuB4X+ofV",
+	"bGFOAafkjw40RZ72z/qTYuLLe0Nu3rkZeptTE/NnbfxTqAFlusUvt7vBDY9vL9J+1EGLFYQUkigLY/b7",
+	"n7VQas/CSNjYQ9tm1ZnDYyzVFQOFxbJIUMtti7WzXrffOZv8dt05719fjE4rHIG5vtlPqIfk5tmS4U7Z",
+	"LZEKWcDtK7piEmjAWAiuOHjf65xO3l9fDDqXnf5p581pMM0OjiW3h4LrIteBNlTDymKOTLupVjnhcl1X",
+	"n/zkqkMz3cmlvvE0LjcAnln+5bQV/zZQqMVqOWWYxpV09FETK+hF4CKHGqxIMpzIBVdIYYgyMxnTk/cX",
+	"Z28Gnf7pdX8w6Y0uO6dOzUDTzRXLHp8Mz846g25TF/8FCuXCKBNMAaXHLTRUCyLWVMKtXEsZYEZEXDEX",
+	"Auy3krU/6R6SWokgak0I0w1kD1/DMypcXckrZspK2jpDMywiNOOxzubGc2V8jFRoU1oLnWdTG43PLNgs",
+	"49VxepFBI4ceWV3C3gRrVgZYHulWMTh2oe5yK7uYpCey17LD6Zkaj4vbuO4wp2szw9gnAICjE86U4HG+",
+	"WbbJEmmiJf5yhG/J318cb68W2AAI7Si3963ZeBE0dwEfgL2jJVazBZGoPz8aQPTVGfwNW+jPj854pLtv",
+	"HJlu7qHSxbq1c1pSOsdMqujmjkeVpALWdVnVgU0W85HzVd/Qga1ifMWeHx8fttB7GkUWjU18EYs3rlcX",
+	"IJa+zFWh0iWPAsEnxai3rM9M94PG+Sait4wLXUcTy6r6i9Hv9ysEqZsxmX14NV6R6+EFen7FTGl98cY9",
+	"Pb1VC9CBoS4iyIX0RVlrWJOsreiS/Mu0qa+Va59rX/OjQiugH2DtiByiy4rsv5aJqSZQsgF8a243A2i3",
+	"lcitPCNPoMiUNttf73iU7+ZRuLp4ZMUIiUre5DR2k+vrrmczaVVQ2r2tVJc8qlc+fJgYybH3pBGNTBV3",
+	"He8YXCDIXBC5yPobXoxO92bmmmSNDBfmaMFywDjK++YRF/5R6tRwr7zcHgxh461WsF0UYOSVD/tdFNB2",
+	"x1Hdk8FWLHa9ILEsdrEsX57SMzZx0K0rdiFtuJHpg3nj9a28MTFQlzz6xURi8kQiKCKtTUugb12xm1Cf",
+	"y5sWuhnBVfFGn+ZNf37k/hSZiTmykVFXrLAqbfZJdJtoHf6PJCGf04BQzGQWxJRgoSiOr5g9/JA8PY/x",
+	"5mFkHpIi1ixXL6aJMvXqZbDrdHhwOAysVmJ747mqJnHF2tMj2+/rIaWW72hEeHuZvHwUbVX4QajPj189",
+	"8WRabdcVclTaUW2ejxUtKMpmLQZrd2u5L49f7E8G615wxoabokkWMDDuvxt0Jhej3uGP4ozQseDVnux2",
+	"6clmzUVzQWb2cL14is7gnQHNz8fP97PIAnMDT3AcaYDpH4orvRx2C3aUPdl5trc4Di0sdyMDLntfEXeX",
+	"ttqurMww0A0pcjl5fJ6VPMhnDuzW+AJdvn+k+vf4voctbcz3XL6gQgfN1pXP+/wxLtUUqdo/xLv6MKXy",
+	"3NhLtVMCeF4N6nMtlqsdqh9BzcpnV9rW0gnALtfJ2ZZFMvXEoYHzgsf2sQ5dnwtCmvqJjbz0O1dv0K22",
+	"KYBLchavJHgfwU9h/HtzLq7Yx05/ctofT65PTjv9M+csNPHzuos0LCKLcHdh7W6T1/qdmyuWhbYbA4Hu",
+	"Lkrn5gbRBGeOzhiFbazJdMH5ZzOHXuZP0rlt9Z5Sv6z+7bXGINuoxHfpKm7dwHWSjj5wylwL8yfySfpT",
+	"/CCPZL5JeyhWN8ouBWkz8B9XHy7rol7loswJQIBwbuXGzKcdYySq3XnXfd3+qrFvR9DjR6oWkcBrg5f6",
+	"ixbqMK0GKcyMK9xRiiAxwdLSK+CtNaLxzAJvRigh6CnBd8TD0N2JH3bxD035KLZO+UxYFk2hb4S/c8oc",
+	"Y1pnKwxehH47cns4cu1n6t+uakVynpK5CqDvHrMZ9ivANC2X2g5Ch0gibN+JApmcltr9b6EH+FJrzQbP",
+	"ViK2Hepft9sxn+F4waV6/bfjvx23oZLN3bPGt0/f/mcAsOeIvtXRAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
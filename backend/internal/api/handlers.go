@@ -1,21 +1,32 @@
 package api
 
 import (
+	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
+	"github.com/dj-event/stream-system/internal/cluster"
 	"github.com/dj-event/stream-system/internal/config"
 	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/ingest"
+	"github.com/dj-event/stream-system/internal/mail"
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/dj-event/stream-system/internal/restream"
 	"github.com/dj-event/stream-system/internal/websocket"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	gorillaWs "github.com/gorilla/websocket"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/image/font/sfnt"
 )
 
 type Handler struct {
@@ -23,26 +34,153 @@ type Handler struct {
 	logger    *logrus.Logger
 	config    *config.Config
 	wsManager *websocket.Manager
+	mailer    *mail.Mailer
+	mediamtx  *mediamtx.Client
+	ingest    *ingest.Monitor
+	djConns   djConns
+	tokens    *websocket.TokenSigner
 	upgrader  gorillaWs.Upgrader
+	// urlSecret signs VOD playback URLs (VOD_URL_SECRET)
+	urlSecret []byte
+	// fallbackReady is set once the fallback path is configured in MediaMTX
+	fallbackReady atomic.Bool
+	// thumbnail is the latest snapshot of the live stream (nil while offline)
+	thumbnail atomic.Pointer[thumbnail]
+	// restream runs this replica's forwarders; restreamWake triggers a
+	// reconcile before the next tick
+	restream     *restream.Supervisor
+	restreamWake chan struct{}
+	// placeholderFont draws the DJ name on offline thumbnails
+	placeholderFont *sfnt.Font
+	// placeholder is the offline thumbnail of the current or next DJ
+	placeholder atomic.Pointer[placeholder]
 }
 
 func NewHandler(database *db.DB, logger *logrus.Logger, cfg *config.Config) *Handler {
-	wsManager := websocket.NewManager(logger)
-	go wsManager.Run()
+	mailer := mail.NewMailer(mail.Config{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	}, logger)
+
+	secret := []byte(cfg.WebSocket.ViewerTokenSecret)
+	if len(secret) == 0 {
+		logger.Warn("VIEWER_TOKEN_SECRET is not set, viewer tokens will be reset on restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatalf("Failed to generate viewer token secret: %v", err)
+		}
+	}
+	urlSecret := []byte(cfg.Media.VODURLSecret)
+	if len(urlSecret) == 0 {
+		logger.Warn("VOD_URL_SECRET is not set, VOD playback URLs will expire on restart")
+		urlSecret = secret
+	}
 
-	return &Handler{
+	h := &Handler{
 		db:        database,
 		logger:    logger,
 		config:    cfg,
-		wsManager: wsManager,
-		upgrader: gorillaWs.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				// Allow all origins in development
-				// In production, you should check the origin
-				return true
-			},
+		mailer:    mailer,
+		tokens:    websocket.NewTokenSigner(secret),
+		urlSecret: urlSecret,
+	}
+
+	var bridge *cluster.Bridge
+	if cfg.Cluster.Enabled {
+		var err error
+		bridge, err = cluster.New(cluster.Config{
+			DSN: db.Config{
+				Host:     cfg.Database.Host,
+				Port:     cfg.Database.Port,
+				User:     cfg.Database.User,
+				Password: cfg.Database.Password,
+				DBName:   cfg.Database.DBName,
+				SSLMode:  cfg.Database.SSLMode,
+			}.DSN(),
+			ReplicaID: cfg.Cluster.ReplicaID,
+		}, database, logger)
+		if err != nil {
+			logger.Fatalf("Failed to start cluster bridge: %v", err)
+		}
+	}
+
+	wsConfig := websocket.Config{
+		MaxConnectionsPerIP: cfg.WebSocket.MaxConnectionsPerIP,
+		MaxMessageSize:      cfg.WebSocket.MaxMessageSize,
+		ViewerCountDebounce: cfg.WebSocket.ViewerCountDebounce,
+		Chat: websocket.ChatConfig{
+			MaxLength:    cfg.Chat.MaxLength,
+			HistorySize:  cfg.Chat.HistorySize,
+			RateBurst:    cfg.Chat.RateBurst,
+			RateInterval: cfg.Chat.RateInterval,
+			OnMessage:    h.saveChatMessage,
+		},
+		Reactions: websocket.ReactionConfig{
+			Allowed:        cfg.Reactions.Allowed,
+			RateBurst:      cfg.Reactions.RateBurst,
+			RateInterval:   cfg.Reactions.RateInterval,
+			OnMinuteTotals: h.saveReactionTotals,
 		},
+		Moderation: websocket.ModerationConfig{
+			Moderators:       cfg.Moderation.Moderators,
+			BlockedWords:     cfg.Moderation.BlockedWords,
+			SubscriberMinAge: cfg.Moderation.SubscriberMinAge,
+			OnAction:         h.saveModerationAction,
+			LookupMessage:    h.lookupChatMessage,
+		},
+	}
+	if bridge != nil {
+		wsConfig.Cluster = bridge
+	}
+	h.wsManager = websocket.NewManager(wsConfig, logger)
+	h.loadChatBans()
+	go h.wsManager.Run()
+	if bridge != nil {
+		go bridge.Run(h.wsManager.HandleClusterEvent)
+	}
+
+	h.upgrader = gorillaWs.Upgrader{
+		CheckOrigin: h.checkOrigin,
+	}
+
+	go h.expireSlotHolds()
+
+	if cfg.MediaMTX.APIURL != "" {
+		h.mediamtx = mediamtx.NewClient(cfg.MediaMTX.APIURL)
+		go h.pollExternalViewers()
+
+		h.ingest = ingest.NewMonitor(h.mediamtx, ingest.Config{
+			PathName:           cfg.MediaMTX.PathName,
+			PollInterval:       cfg.Ingest.PollInterval,
+			History:            cfg.Ingest.History,
+			BitrateDropPercent: cfg.Ingest.BitrateDropPercent,
+			ReconnectLimit:     cfg.Ingest.ReconnectLimit,
+			ReconnectWindow:    cfg.Ingest.ReconnectWindow,
+			OnWarning:          h.sendIngestWarning,
+		}, logger)
+		go h.ingest.Run()
+
+		if cfg.MediaMTX.FallbackFile != "" {
+			go h.maintainFallback()
+		}
 	}
+	go h.enforceSlotEnds()
+	h.placeholderFont = loadPlaceholderFont(cfg.Thumbnail.FontFile, logger)
+	h.refreshPlaceholder()
+	go h.generateThumbnails()
+
+	h.restreamWake = make(chan struct{}, 1)
+	h.restream = restream.NewSupervisor(restream.Config{
+		FFmpeg:    cfg.Restream.FFmpeg,
+		SourceURL: cfg.Restream.SourceURL,
+		OnEvent:   h.onRestreamEvent,
+	}, logger)
+	go h.superviseRestreams()
+
+	return h
 }
 
 func (h *Handler) GetStreamStatus(w http.ResponseWriter, r *http.Request) {
@@ -55,22 +193,68 @@ func (h *Handler) GetStreamStatus(w http.ResponseWriter, r *http.Request) {
 	// Check if stream is live by checking HLS file exists and is recent
 	isLive := h.checkStreamIsLive()
 
-	viewerCount := h.wsManager.GetViewerCount()
+	counts := h.wsManager.GetViewerCounts()
+	viewerCount := counts.Total()
 
 	status := StreamStatus{
-		IsLive:      isLive,
-		ViewerCount: &viewerCount,
+		IsLive:        isLive,
+		State:         Offline,
+		ViewerCount:   &viewerCount,
+		Connections:   &counts.Connections,
+		UniqueViewers: &counts.UniqueViewers,
+	}
+	switch {
+	case isLive:
+		status.State = Live
+		playbackPath := hlsPlaylist(h.config.MediaMTX.PathName)
+		status.PlaybackPath = &playbackPath
+	case h.fallbackActive(time.Now()):
+		status.State = Fallback
+		playbackPath := hlsPlaylist(h.config.MediaMTX.FallbackPath)
+		status.PlaybackPath = &playbackPath
+	}
+	if h.mediamtx != nil {
+		status.ExternalViewers = &counts.ExternalViewers
+	}
+
+	var ids []uuid.UUID
+	if currentNext.CurrentID != nil {
+		ids = append(ids, *currentNext.CurrentID)
+	}
+	if currentNext.NextID != nil {
+		ids = append(ids, *currentNext.NextID)
+	}
+	lineups, err := h.getLineups(ids)
+	if err != nil {
+		h.logger.Errorf("Failed to get lineups: %v", err)
 	}
 
 	if currentNext.CurrentDJName != nil {
 		status.CurrentDj = currentNext.CurrentDJName
 		status.CurrentStartTime = currentNext.CurrentStartTime
 		status.CurrentEndTime = currentNext.CurrentEndTime
+		if currentNext.CurrentID != nil && len(lineups[*currentNext.CurrentID]) > 0 {
+			performers := lineups[*currentNext.CurrentID]
+			status.CurrentPerformers = &performers
+		}
+		if currentNext.CurrentID != nil {
+			track, err := h.db.GetCurrentTrack(*currentNext.CurrentID, time.Now())
+			if err != nil {
+				h.logger.Errorf("Failed to get current track: %v", err)
+			} else if track != nil {
+				apiTrack := toAPITrack(*track)
+				status.CurrentTrack = &apiTrack
+			}
+		}
 	}
 
 	if currentNext.NextDJName != nil {
 		status.NextDj = currentNext.NextDJName
 		status.NextStartTime = currentNext.NextStartTime
+		if currentNext.NextID != nil && len(lineups[*currentNext.NextID]) > 0 {
+			performers := lineups[*currentNext.NextID]
+			status.NextPerformers = &performers
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -99,14 +283,27 @@ func (h *Handler) GetReservations(w http.ResponseWriter, r *http.Request) {
 		filteredReservations = append(filteredReservations, res)
 	}
 
+	ids := make([]uuid.UUID, len(filteredReservations))
+	for i, res := range filteredReservations {
+		ids[i] = res.ID
+	}
+	lineups, err := h.getLineups(ids)
+	if err != nil {
+		h.logger.Errorf("Failed to get lineups: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get reservations")
+		return
+	}
+
 	apiReservations := make([]Reservation, len(filteredReservations))
 	for i, res := range filteredReservations {
 		apiReservations[i] = Reservation{
-			Id:        openapi_types.UUID(res.ID),
-			DjName:    res.DJName,
-			StartTime: res.StartTime,
-			EndTime:   res.EndTime,
-			CreatedAt: res.CreatedAt,
+			Id:         openapi_types.UUID(res.ID),
+			DjName:     res.DJName,
+			Performers: lineups[res.ID],
+			StartTime:  res.StartTime,
+			EndTime:    res.EndTime,
+			Consent:    toAPIConsent(res.Consent),
+			CreatedAt:  res.CreatedAt,
 		}
 	}
 
@@ -114,73 +311,176 @@ func (h *Handler) GetReservations(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(apiReservations)
 }
 
-var passcodePattern = regexp.MustCompile(`^[0-9]{4}$`)
+// getLineups returns the lineups of the given reservations with the DJ
+// profiles attached to their performers
+func (h *Handler) getLineups(reservationIDs []uuid.UUID) (map[uuid.UUID][]Performer, error) {
+	performers, err := h.db.GetPerformers(reservationIDs)
+	if err != nil {
+		return nil, err
+	}
 
-func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
-	var req CreateReservationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
-		return
+	var profileIDs []uuid.UUID
+	for _, lineup := range performers {
+		for _, performer := range lineup {
+			if performer.ProfileID != nil {
+				profileIDs = append(profileIDs, *performer.ProfileID)
+			}
+		}
 	}
+	profiles, err := h.db.GetDJProfiles(profileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lineups := make(map[uuid.UUID][]Performer, len(reservationIDs))
+	for _, id := range reservationIDs {
+		lineups[id] = []Performer{}
+	}
+	for id, lineup := range performers {
+		apiLineup := make([]Performer, len(lineup))
+		for i, performer := range lineup {
+			apiLineup[i] = Performer{Position: performer.Position, DjName: performer.DJName}
+			if performer.ProfileID == nil {
+				continue
+			}
+			if profile, ok := profiles[*performer.ProfileID]; ok {
+				apiProfile := h.toAPIDJProfile(profile)
+				apiLineup[i].Profile = &apiProfile
+			}
+		}
+		lineups[id] = apiLineup
+	}
+	return lineups, nil
+}
 
-	// Validate DJ name (1-100 characters, rune-based for emoji support)
-	djNameLen := utf8.RuneCountInString(req.DjName)
-	if djNameLen == 0 {
-		h.sendError(w, http.StatusBadRequest, "INVALID_DJ_NAME", "DJ name is required")
-		return
+var (
+	numericPasscodePattern      = regexp.MustCompile(`^[0-9]+$`)
+	alphanumericPasscodePattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+)
+
+// validatePasscode checks a passcode against the configured strength rules.
+// It returns a user-facing message describing the violation, or "" if valid.
+func (h *Handler) validatePasscode(passcode string) string {
+	policy := h.config.Passcode
+
+	var lengthRule string
+	if policy.MinLength == policy.MaxLength {
+		lengthRule = fmt.Sprintf("exactly %d", policy.MinLength)
+	} else {
+		lengthRule = fmt.Sprintf("%d-%d", policy.MinLength, policy.MaxLength)
 	}
-	if djNameLen > 100 {
-		h.sendError(w, http.StatusBadRequest, "INVALID_DJ_NAME", "DJ name must be at most 100 characters")
-		return
+
+	length := utf8.RuneCountInString(passcode)
+	valid := length >= policy.MinLength && length <= policy.MaxLength
+
+	switch policy.Charset {
+	case config.PasscodeCharsetNumeric:
+		if !valid || !numericPasscodePattern.MatchString(passcode) {
+			return fmt.Sprintf("Passcode must be %s digits", lengthRule)
+		}
+	case config.PasscodeCharsetAlphanumeric:
+		if !valid || !alphanumericPasscodePattern.MatchString(passcode) {
+			return fmt.Sprintf("Passcode must be %s letters or digits", lengthRule)
+		}
+	default:
+		// bcrypt only looks at the first 72 bytes, so multi-byte passphrases
+		// are limited by their encoded size as well
+		if !valid || len(passcode) > 72 || strings.IndexFunc(passcode, unicode.IsControl) >= 0 {
+			return fmt.Sprintf("Passcode must be %s characters", lengthRule)
+		}
 	}
 
-	// Validate passcode (4 digits)
-	if !passcodePattern.MatchString(req.Passcode) {
-		h.sendError(w, http.StatusBadRequest, "INVALID_PASSCODE", "Passcode must be exactly 4 digits")
+	return ""
+}
+
+func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, openapi_types.ErrValidationEmail) {
+			h.sendError(w, http.StatusBadRequest, "INVALID_EMAIL", "Contact email is not a valid email address")
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
 		return
 	}
 
-	if req.StartTime.Minute()%15 != 0 || req.StartTime.Second() != 0 {
-		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_INTERVAL", "Start time must be on 15-minute intervals")
+	if !h.validateDJName(w, req.DjName) {
 		return
 	}
 
-	if req.EndTime.Minute()%15 != 0 || req.EndTime.Second() != 0 {
-		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_INTERVAL", "End time must be on 15-minute intervals")
+	// Validate passcode against the configured policy
+	if msg := h.validatePasscode(req.Passcode); msg != "" {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PASSCODE", msg)
 		return
 	}
 
-	if req.StartTime.Before(time.Now()) {
-		h.sendError(w, http.StatusBadRequest, "PAST_TIME", "Cannot create reservation in the past")
+	// The booking DJ plays first, B2B partners follow in the given order
+	profile, ok := h.resolveProfileToken(w, req.ProfileToken)
+	if !ok {
 		return
 	}
+	performers := []db.NewPerformer{{DJName: req.DjName, Passcode: req.Passcode, ProfileID: profileID(profile)}}
+	profiles := []*db.DJProfile{profile}
+	if req.AdditionalPerformers != nil {
+		if len(*req.AdditionalPerformers) > db.MaxPerformers-1 {
+			h.sendError(w, http.StatusBadRequest, "TOO_MANY_PERFORMERS", fmt.Sprintf("A reservation can have at most %d performers", db.MaxPerformers))
+			return
+		}
+		for _, performer := range *req.AdditionalPerformers {
+			if !h.validateDJName(w, performer.DjName) {
+				return
+			}
+			if msg := h.validatePasscode(performer.Passcode); msg != "" {
+				h.sendError(w, http.StatusBadRequest, "INVALID_PASSCODE", fmt.Sprintf("%s: %s", performer.DjName, msg))
+				return
+			}
+			profile, ok := h.resolveProfileToken(w, performer.ProfileToken)
+			if !ok {
+				return
+			}
+			performers = append(performers, db.NewPerformer{DJName: performer.DjName, Passcode: performer.Passcode, ProfileID: profileID(profile)})
+			profiles = append(profiles, profile)
+		}
+	}
 
-	if !req.EndTime.After(req.StartTime) {
-		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_RANGE", "End time must be after start time")
-		return
+	// Validate optional contact email
+	var contactEmail *string
+	if req.ContactEmail != nil {
+		email := string(*req.ContactEmail)
+		if len(email) > 254 {
+			h.sendError(w, http.StatusBadRequest, "INVALID_EMAIL", "Contact email must be at most 254 characters")
+			return
+		}
+		contactEmail = &email
 	}
 
-	if req.EndTime.Sub(req.StartTime) > time.Hour {
-		h.sendError(w, http.StatusBadRequest, "DURATION_TOO_LONG", "Reservation duration cannot exceed 1 hour")
+	if !h.validateSlotRange(w, req.StartTime, req.EndTime) {
 		return
 	}
 
-	// Check if reservation start time is before event start time
-	if h.config.EventStartTime != nil && req.StartTime.Before(*h.config.EventStartTime) {
-		h.sendError(w, http.StatusBadRequest, "BEFORE_EVENT_START", "Reservation cannot start before event start time")
-		return
+	var holdTokenHash string
+	if req.HoldToken != nil && *req.HoldToken != "" {
+		holdTokenHash = hashSecretToken(*req.HoldToken)
 	}
 
-	// Check if reservation end time exceeds event end time
-	if h.config.EventEndTime != nil && req.EndTime.After(*h.config.EventEndTime) {
-		h.sendError(w, http.StatusBadRequest, "EXCEEDS_EVENT_END", "Reservation cannot extend beyond event end time")
-		return
+	// DJs consent to everything unless they opt out
+	consent := db.Consent{Record: true, Restream: true, PublicVOD: true}
+	if req.Consent != nil {
+		consent = fromAPIConsent(*req.Consent)
 	}
 
-	reservation, err := h.db.CreateReservation(req.DjName, req.StartTime, req.EndTime, req.Passcode)
+	reservation, err := h.db.CreateReservation(performers, req.StartTime, req.EndTime, contactEmail, consent, holdTokenHash)
 	if err != nil {
-		h.logger.Errorf("Failed to create reservation: %v", err)
 		errStr := err.Error()
+		if errStr == "slot held" {
+			h.sendError(w, http.StatusConflict, "SLOT_HELD", "Time slot is being booked by someone else")
+			return
+		}
+		if errStr == "invalid hold" {
+			h.sendError(w, http.StatusConflict, "INVALID_HOLD", "Slot hold has expired or does not cover the requested time")
+			return
+		}
+		h.logger.Errorf("Failed to create reservation: %v", err)
 		if strings.Contains(errStr, "no_overlap") {
 			h.sendError(w, http.StatusConflict, "TIME_CONFLICT", "Time slot is already reserved")
 		} else if strings.Contains(errStr, "valid_time_range") {
@@ -195,12 +495,25 @@ func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.announceLineupChange(websocket.LineupCreated, reservation)
+
+	lineup := make([]Performer, len(performers))
+	for i, performer := range performers {
+		lineup[i] = Performer{Position: i + 1, DjName: performer.DJName}
+		if profiles[i] != nil {
+			apiProfile := h.toAPIDJProfile(*profiles[i])
+			lineup[i].Profile = &apiProfile
+		}
+	}
+
 	apiReservation := Reservation{
-		Id:        openapi_types.UUID(reservation.ID),
-		DjName:    reservation.DJName,
-		StartTime: reservation.StartTime,
-		EndTime:   reservation.EndTime,
-		CreatedAt: reservation.CreatedAt,
+		Id:         openapi_types.UUID(reservation.ID),
+		DjName:     reservation.DJName,
+		Performers: lineup,
+		StartTime:  reservation.StartTime,
+		EndTime:    reservation.EndTime,
+		Consent:    toAPIConsent(reservation.Consent),
+		CreatedAt:  reservation.CreatedAt,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -208,6 +521,64 @@ func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(apiReservation)
 }
 
+// validateDJName checks that a DJ name has 1-100 characters (rune-based for
+// emoji support), replying with an error otherwise
+func (h *Handler) validateDJName(w http.ResponseWriter, name string) bool {
+	djNameLen := utf8.RuneCountInString(name)
+	if djNameLen == 0 {
+		h.sendError(w, http.StatusBadRequest, "INVALID_DJ_NAME", "DJ name is required")
+		return false
+	}
+	if djNameLen > 100 {
+		h.sendError(w, http.StatusBadRequest, "INVALID_DJ_NAME", "DJ name must be at most 100 characters")
+		return false
+	}
+	return true
+}
+
+// validateSlotRange checks the time rules shared by reservations and slot
+// holds, replying with an error if they are violated
+func (h *Handler) validateSlotRange(w http.ResponseWriter, startTime, endTime time.Time) bool {
+	if startTime.Minute()%15 != 0 || startTime.Second() != 0 {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_INTERVAL", "Start time must be on 15-minute intervals")
+		return false
+	}
+
+	if endTime.Minute()%15 != 0 || endTime.Second() != 0 {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_INTERVAL", "End time must be on 15-minute intervals")
+		return false
+	}
+
+	if startTime.Before(time.Now()) {
+		h.sendError(w, http.StatusBadRequest, "PAST_TIME", "Cannot create reservation in the past")
+		return false
+	}
+
+	if !endTime.After(startTime) {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TIME_RANGE", "End time must be after start time")
+		return false
+	}
+
+	if endTime.Sub(startTime) > time.Hour {
+		h.sendError(w, http.StatusBadRequest, "DURATION_TOO_LONG", "Reservation duration cannot exceed 1 hour")
+		return false
+	}
+
+	// Check if reservation start time is before event start time
+	if h.config.EventStartTime != nil && startTime.Before(*h.config.EventStartTime) {
+		h.sendError(w, http.StatusBadRequest, "BEFORE_EVENT_START", "Reservation cannot start before event start time")
+		return false
+	}
+
+	// Check if reservation end time exceeds event end time
+	if h.config.EventEndTime != nil && endTime.After(*h.config.EventEndTime) {
+		h.sendError(w, http.StatusBadRequest, "EXCEEDS_EVENT_END", "Reservation cannot extend beyond event end time")
+		return false
+	}
+
+	return true
+}
+
 func (h *Handler) DeleteReservation(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "reservationId")
 	id, err := uuid.Parse(idStr)
@@ -224,7 +595,7 @@ func (h *Handler) DeleteReservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.db.DeleteReservation(id, req.Passcode)
+	reservation, err := h.db.DeleteReservation(id, req.Passcode)
 	if err != nil {
 		if err.Error() == "invalid passcode" {
 			h.sendError(w, http.StatusUnauthorized, "INVALID_PASSCODE", "Invalid passcode")
@@ -239,12 +610,33 @@ func (h *Handler) DeleteReservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.announceLineupChange(websocket.LineupDeleted, reservation)
+	h.offerWaitlist(reservation.StartTime, reservation.EndTime)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// announceLineupChange tells every viewer and booking page that the
+// reservation's time range changed. Any handler that adds, removes or moves
+// a reservation must call it.
+func (h *Handler) announceLineupChange(reason string, reservation *db.Reservation) {
+	h.wsManager.Broadcast(websocket.TypeLineupChanged, websocket.LineupChangedPayload{
+		Reason:        reason,
+		ReservationID: &reservation.ID,
+		StartTime:     reservation.StartTime,
+		EndTime:       reservation.EndTime,
+	})
+}
+
 func (h *Handler) GetEventConfig(w http.ResponseWriter, r *http.Request) {
 	config := EventConfig{
 		Timezone: h.config.EventTimezone,
+		PasscodePolicy: PasscodePolicy{
+			MinLength: h.config.Passcode.MinLength,
+			MaxLength: h.config.Passcode.MaxLength,
+			Charset:   PasscodePolicyCharset(h.config.Passcode.Charset),
+		},
+		PasscodeRecoveryEnabled: h.mailer.Enabled(),
 	}
 
 	if h.config.EventStartTime != nil {
@@ -322,10 +714,17 @@ func (h *Handler) GetAvailableSlots(w http.ResponseWriter, r *http.Request) {
 
 	apiSlots := make([]TimeSlot, len(slots))
 	for i, slot := range slots {
+		state := Available
+		if slot.Held {
+			state = Held
+		} else if !slot.Available {
+			state = Reserved
+		}
 		apiSlots[i] = TimeSlot{
 			StartTime: slot.StartTime,
 			EndTime:   slot.EndTime,
 			Available: slot.Available,
+			State:     state,
 		}
 	}
 
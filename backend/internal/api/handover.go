@@ -0,0 +1,421 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/google/uuid"
+	gorillaWs "github.com/gorilla/websocket"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	// handoverInterval is how often the DJ channel pushes the handover status
+	handoverInterval = 5 * time.Second
+	// djPresenceTTL is how long a DJ counts as ready after their DJ channel
+	// or overlay was last seen
+	djPresenceTTL = 3 * handoverInterval
+	djAuthTimeout = 10 * time.Second
+	djPongWait    = 60 * time.Second
+	djPingPeriod  = 54 * time.Second
+	djWriteWait   = 10 * time.Second
+	// djPushBuffer is how many pushed events may wait for a slow connection
+	djPushBuffer = 8
+)
+
+// handoverWarnings are the remaining times announced to the DJ on air
+var handoverWarnings = []time.Duration{10 * time.Minute, 5 * time.Minute}
+
+// GetHandover returns the handover countdown of a reservation for overlays
+func (h *Handler) GetHandover(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authenticateReservation(w, r)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.db.GetReservation(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get handover status")
+		return
+	}
+
+	now := time.Now()
+	h.touchDJPresence(id, now)
+
+	status, err := h.handoverStatus(reservation, now)
+	if err != nil {
+		h.logger.Errorf("Failed to get handover status: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get handover status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// djConn is an authenticated DJ channel connection
+type djConn struct {
+	// reservation is nil for admins
+	reservation *db.Reservation
+	admin       string
+	// push carries encoded events for the connection's write loop
+	push chan []byte
+}
+
+// djConns tracks the DJ channel connections of this replica
+type djConns struct {
+	mu    sync.Mutex
+	conns map[*djConn]struct{}
+}
+
+func (c *djConns) add(conn *djConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conns == nil {
+		c.conns = make(map[*djConn]struct{})
+	}
+	c.conns[conn] = struct{}{}
+}
+
+func (c *djConns) remove(conn *djConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.conns, conn)
+}
+
+// send pushes a message to the matching connections. Connections that are
+// too far behind miss it.
+func (c *djConns) send(data []byte, match func(*djConn) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for conn := range c.conns {
+		if !match(conn) {
+			continue
+		}
+		select {
+		case conn.push <- data:
+		default:
+		}
+	}
+}
+
+// HandleDJWebSocket serves the DJ channel. The first message must be dj_auth
+// with the reservation ID and passcode. The server then pushes the handover
+// status every few seconds together with the countdown events. Admins send
+// admin_auth instead and only receive ingest warnings.
+func (h *Handler) HandleDJWebSocket(w http.ResponseWriter, r *http.Request) {
+	ip := clientIP(r)
+	if !h.wsManager.AcquireIP(ip) {
+		h.logger.Warnf("Rejected DJ channel connection from %s: too many connections", ip)
+		h.sendError(w, http.StatusTooManyRequests, "TOO_MANY_CONNECTIONS", "Too many connections from this address")
+		return
+	}
+	defer h.wsManager.ReleaseIP(ip)
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Errorf("Failed to upgrade connection: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(h.config.WebSocket.MaxMessageSize)
+
+	dc, ok := h.authenticateDJConn(conn)
+	if !ok {
+		return
+	}
+
+	if dc.admin != "" {
+		h.logger.Infof("Admin %s opened the DJ channel", dc.admin)
+	}
+	h.djConns.add(dc)
+	defer h.djConns.remove(dc)
+	h.runDJChannel(conn, dc)
+}
+
+// authenticateDJConn waits for the dj_auth or admin_auth message. The
+// connection is closed with an error message when it returns false.
+func (h *Handler) authenticateDJConn(conn *gorillaWs.Conn) (*djConn, bool) {
+	authType := websocket.TypeDJAuth
+	reject := func(code, message string) (*djConn, bool) {
+		h.writeDJMessage(conn, websocket.TypeError, websocket.ErrorPayload{Code: code, Message: message, Type: authType})
+		_ = conn.WriteControl(gorillaWs.CloseMessage,
+			gorillaWs.FormatCloseMessage(gorillaWs.ClosePolicyViolation, message), time.Now().Add(djWriteWait))
+		return nil, false
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(djAuthTimeout))
+	var env websocket.Envelope
+	if err := conn.ReadJSON(&env); err != nil {
+		return reject(websocket.ErrorInvalidMessage, "Expected a dj_auth message")
+	}
+	if env.Version != 0 && env.Version != websocket.ProtocolVersion {
+		return reject(websocket.ErrorUnsupportedVersion, "Unsupported protocol version")
+	}
+	if env.Type == websocket.TypeAdminAuth {
+		authType = websocket.TypeAdminAuth
+		var auth websocket.AdminAuthPayload
+		if err := json.Unmarshal(env.Payload, &auth); err != nil {
+			return reject(websocket.ErrorInvalidPayload, "token is required")
+		}
+		name, ok := h.adminName(auth.Token)
+		if !ok {
+			return reject(websocket.ErrorInvalidToken, "Invalid admin token")
+		}
+		return &djConn{admin: name, push: make(chan []byte, djPushBuffer)}, true
+	}
+	if env.Type != websocket.TypeDJAuth {
+		return reject(websocket.ErrorAuthRequired, "The first message must be dj_auth or admin_auth")
+	}
+
+	var auth websocket.DJAuthPayload
+	if err := json.Unmarshal(env.Payload, &auth); err != nil || auth.ReservationID == nil {
+		return reject(websocket.ErrorInvalidPayload, "reservationId and passcode are required")
+	}
+
+	if err := h.db.VerifyReservationPasscode(*auth.ReservationID, auth.Passcode); err != nil {
+		if err.Error() != "invalid passcode" && err.Error() != "reservation not found" {
+			h.logger.Errorf("Failed to verify passcode: %v", err)
+		}
+		return reject(websocket.ErrorInvalidPasscode, "Invalid reservation or passcode")
+	}
+
+	reservation, err := h.db.GetReservation(*auth.ReservationID)
+	if err != nil {
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		return reject(websocket.ErrorInvalidPasscode, "Invalid reservation or passcode")
+	}
+	return &djConn{reservation: reservation, push: make(chan []byte, djPushBuffer)}, true
+}
+
+// runDJChannel pushes the handover status and events until the DJ or
+// admin disconnects. Client messages after authentication are ignored.
+func (h *Handler) runDJChannel(conn *gorillaWs.Conn, dc *djConn) {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		_ = conn.SetReadDeadline(time.Now().Add(djPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(djPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	pingTicker := time.NewTicker(djPingPeriod)
+	defer pingTicker.Stop()
+
+	// Admins get no handover status
+	var tick <-chan time.Time
+	var tracker handoverTracker
+	if dc.reservation != nil {
+		ticker := time.NewTicker(handoverInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+
+		if !h.pushHandover(conn, dc.reservation, &tracker, time.Now()) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-closed:
+			return
+		case now := <-tick:
+			if !h.pushHandover(conn, dc.reservation, &tracker, now) {
+				return
+			}
+		case data := <-dc.push:
+			_ = conn.SetWriteDeadline(time.Now().Add(djWriteWait))
+			if err := conn.WriteMessage(gorillaWs.TextMessage, data); err != nil {
+				return
+			}
+		case <-pingTicker.C:
+			if err := conn.WriteControl(gorillaWs.PingMessage, nil, time.Now().Add(djWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handoverTracker remembers which one-off events a DJ channel connection
+// has already received
+type handoverTracker struct {
+	warned    map[time.Duration]bool
+	nextReady bool
+	ended     bool
+}
+
+// pushHandover sends the current status and any events that became due. It
+// returns false when the connection is gone.
+func (h *Handler) pushHandover(conn *gorillaWs.Conn, reservation *db.Reservation, tracker *handoverTracker, now time.Time) bool {
+	h.touchDJPresence(reservation.ID, now)
+
+	status, err := h.handoverStatus(reservation, now)
+	if err != nil {
+		// Keep the connection; the next tick retries
+		h.logger.Errorf("Failed to get handover status: %v", err)
+		return true
+	}
+
+	if !h.writeDJMessage(conn, websocket.TypeHandoverStatus, status) {
+		return false
+	}
+
+	switch status.State {
+	case OnAir:
+		if tracker.warned == nil {
+			tracker.warned = make(map[time.Duration]bool)
+		}
+		// Only the shortest warning that is due is sent, e.g. a DJ who
+		// connects with 7 minutes left gets the 10 minute warning only
+		left := reservation.EndTime.Sub(now)
+		var due time.Duration
+		for _, warning := range handoverWarnings {
+			if left <= warning && !tracker.warned[warning] {
+				tracker.warned[warning] = true
+				due = warning
+			}
+		}
+		if due > 0 && !h.writeDJMessage(conn, websocket.TypeTimeWarning, websocket.TimeWarningPayload{
+			MinutesLeft: int(due / time.Minute),
+			EndTime:     reservation.EndTime,
+		}) {
+			return false
+		}
+
+		ready := status.NextDj != nil && status.NextDj.Ready
+		if ready && !tracker.nextReady && !h.writeDJMessage(conn, websocket.TypeNextDJReady, websocket.NextDJReadyPayload{
+			ReservationID: uuid.UUID(status.NextDj.ReservationId),
+			DJName:        status.NextDj.DjName,
+			StartTime:     status.NextDj.StartTime,
+		}) {
+			return false
+		}
+		tracker.nextReady = ready
+
+	case Ended:
+		if tracker.ended {
+			break
+		}
+		tracker.ended = true
+		payload := websocket.SlotEndedPayload{
+			ReservationID: reservation.ID,
+			DJName:        reservation.DJName,
+			EndTime:       reservation.EndTime,
+		}
+		if next, err := h.db.GetNextReservation(reservation.EndTime); err != nil {
+			h.logger.Errorf("Failed to get next reservation: %v", err)
+		} else if next != nil {
+			payload.NextReservationID = &next.ID
+			payload.NextDJName = &next.DJName
+			payload.NextStartTime = &next.StartTime
+		}
+		if !h.writeDJMessage(conn, websocket.TypeSlotEnded, payload) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// handoverStatus describes where a reservation stands relative to the
+// current and next DJ
+func (h *Handler) handoverStatus(reservation *db.Reservation, now time.Time) (HandoverStatus, error) {
+	status := HandoverStatus{
+		ReservationId:     openapi_types.UUID(reservation.ID),
+		DjName:            reservation.DJName,
+		StartTime:         reservation.StartTime,
+		EndTime:           reservation.EndTime,
+		SecondsLeft:       secondsUntil(now, reservation.EndTime),
+		SecondsUntilStart: secondsUntil(now, reservation.StartTime),
+	}
+
+	currentNext, err := h.db.GetCurrentNextDJ()
+	if err != nil {
+		return status, err
+	}
+
+	var nextID *uuid.UUID
+	var nextDJName *string
+	var nextStartTime *time.Time
+
+	// The view decides who is on air; the time checks only cover clock
+	// differences between the database and the backend
+	onAir := currentNext.CurrentID != nil && *currentNext.CurrentID == reservation.ID
+	switch {
+	case onAir || (!now.Before(reservation.StartTime) && now.Before(reservation.EndTime)):
+		status.State = OnAir
+		if onAir {
+			nextID, nextDJName, nextStartTime = currentNext.NextID, currentNext.NextDJName, currentNext.NextStartTime
+		}
+	case now.Before(reservation.StartTime):
+		status.State = Upcoming
+	default:
+		status.State = Ended
+		return status, nil
+	}
+
+	if nextID == nil {
+		next, err := h.db.GetNextReservation(reservation.EndTime)
+		if err != nil {
+			return status, err
+		}
+		if next == nil {
+			return status, nil
+		}
+		nextID, nextDJName, nextStartTime = &next.ID, &next.DJName, &next.StartTime
+	}
+	if nextDJName == nil || nextStartTime == nil {
+		return status, nil
+	}
+
+	ready, err := h.db.IsDJPresent(*nextID, now.Add(-djPresenceTTL))
+	if err != nil {
+		return status, err
+	}
+	status.NextDj = &HandoverNextDj{
+		ReservationId: openapi_types.UUID(*nextID),
+		DjName:        *nextDJName,
+		StartTime:     *nextStartTime,
+		Ready:         ready,
+	}
+	return status, nil
+}
+
+func (h *Handler) touchDJPresence(reservationID uuid.UUID, now time.Time) {
+	if err := h.db.TouchDJPresence(reservationID, now); err != nil {
+		h.logger.Warnf("Failed to record DJ presence: %v", err)
+	}
+}
+
+// writeDJMessage writes one envelope to a DJ channel connection. It returns
+// false when the connection is gone.
+func (h *Handler) writeDJMessage(conn *gorillaWs.Conn, msgType string, payload any) bool {
+	data, err := websocket.EncodeMessage(msgType, payload)
+	if err != nil {
+		h.logger.Errorf("Failed to encode DJ channel message: %v", err)
+		return true
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(djWriteWait))
+	return conn.WriteMessage(gorillaWs.TextMessage, data) == nil
+}
+
+// secondsUntil rounds the time left until t up to whole seconds, 0 once it passed
+func secondsUntil(now, t time.Time) int {
+	if !now.Before(t) {
+		return 0
+	}
+	return int(math.Ceil(t.Sub(now).Seconds()))
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// slotHoldCleanupInterval is how often expired holds are deleted
+const slotHoldCleanupInterval = 15 * time.Second
+
+// CreateSlotHold keeps a time range for the caller while they fill in the
+// booking form. The returned token is passed as holdToken to CreateReservation.
+// Callers are anonymous, so their active holds are limited per client IP.
+func (h *Handler) CreateSlotHold(w http.ResponseWriter, r *http.Request) {
+	var req CreateSlotHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if !h.validateSlotRange(w, req.StartTime, req.EndTime) {
+		return
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		h.logger.Errorf("Failed to generate hold token: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create slot hold")
+		return
+	}
+
+	booking := h.config.Booking
+	ip := clientIP(r)
+	hold, err := h.db.CreateSlotHold(tokenHash, ip, req.StartTime, req.EndTime, time.Now().Add(booking.HoldDuration),
+		booking.MaxHoldsPerIP, booking.MaxHeldPerIP)
+	if err != nil {
+		switch err.Error() {
+		case "hold limit":
+			h.logger.Warnf("Rejected slot hold from %s: hold limit reached", ip)
+			h.sendError(w, http.StatusTooManyRequests, "HOLD_LIMIT", "You are already holding a time slot; book or wait for it to expire")
+		case "time conflict":
+			h.sendError(w, http.StatusConflict, "TIME_CONFLICT", "Time slot is already reserved")
+		case "slot held":
+			h.sendError(w, http.StatusConflict, "SLOT_HELD", "Time slot is being booked by someone else")
+		default:
+			h.logger.Errorf("Failed to create slot hold: %v", err)
+			h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to create slot hold")
+		}
+		return
+	}
+
+	h.announceHoldChange(websocket.LineupHeld, *hold)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(SlotHold{
+		Id:        openapi_types.UUID(hold.ID),
+		Token:     token,
+		StartTime: hold.StartTime,
+		EndTime:   hold.EndTime,
+		ExpiresAt: hold.ExpiresAt,
+	})
+}
+
+// expireSlotHolds deletes expired holds and tells booking pages that their
+// ranges are free again. Holds consumed by a reservation are announced as
+// created instead.
+func (h *Handler) expireSlotHolds() {
+	ticker := time.NewTicker(slotHoldCleanupInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := h.db.ExpireWaitlistOffers(now); err != nil {
+			h.logger.Warnf("Failed to expire waitlist offers: %v", err)
+		}
+
+		holds, err := h.db.DeleteExpiredSlotHolds(now)
+		if err != nil {
+			h.logger.Warnf("Failed to clean up slot holds: %v", err)
+			continue
+		}
+		for _, hold := range holds {
+			h.announceHoldChange(websocket.LineupReleased, hold)
+			h.offerWaitlist(hold.StartTime, hold.EndTime)
+		}
+	}
+}
+
+func (h *Handler) announceHoldChange(reason string, hold db.SlotHold) {
+	h.wsManager.Broadcast(websocket.TypeLineupChanged, websocket.LineupChangedPayload{
+		Reason:    reason,
+		StartTime: hold.StartTime,
+		EndTime:   hold.EndTime,
+	})
+}
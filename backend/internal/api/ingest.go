@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/ingest"
+	"github.com/dj-event/stream-system/internal/websocket"
+)
+
+// GetStreamHealth returns the ingest health of the publisher with the
+// rolling history
+func (h *Handler) GetStreamHealth(w http.ResponseWriter, r *http.Request) {
+	if h.ingest == nil {
+		h.sendError(w, http.StatusServiceUnavailable, "HEALTH_UNAVAILABLE", "Ingest health requires the MediaMTX API")
+		return
+	}
+
+	health := h.ingest.Health()
+	resp := StreamHealth{
+		Live:       health.Live,
+		Reconnects: max(health.Connections-1, 0),
+		History:    make([]StreamHealthSample, len(health.History)),
+	}
+	if !health.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &health.UpdatedAt
+	}
+	if health.Live {
+		framesInError := int64(health.FramesInError)
+		packetsLost := int64(health.PacketsLost)
+		resp.PublisherType = &health.PublisherType
+		resp.PublisherSince = health.PublisherSince
+		resp.BitrateKbps = roundKbps(health.BitrateKbps)
+		resp.FramesInError = &framesInError
+		resp.PacketsLost = &packetsLost
+		if health.PublisherSince != nil {
+			uptime := int(time.Since(*health.PublisherSince).Seconds())
+			resp.UptimeSeconds = &uptime
+		}
+		if health.VideoCodec != "" {
+			resp.VideoCodec = &health.VideoCodec
+		}
+		if health.AudioCodec != "" {
+			resp.AudioCodec = &health.AudioCodec
+		}
+		if health.Width > 0 && health.Height > 0 {
+			resp.Width = &health.Width
+			resp.Height = &health.Height
+		}
+	}
+	for i, sample := range health.History {
+		resp.History[i] = StreamHealthSample{
+			At:            sample.At,
+			Live:          sample.Live,
+			BitrateKbps:   roundKbps(sample.BitrateKbps),
+			FramesInError: int64(sample.FramesInError),
+			PacketsLost:   int64(sample.PacketsLost),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// sendIngestWarning pushes a warning to admins and the DJ on air. Every
+// replica runs its own monitor, so only local connections are notified.
+func (h *Handler) sendIngestWarning(warning ingest.Warning) {
+	payload := websocket.IngestWarningPayload{
+		Kind: warning.Kind,
+		At:   warning.At,
+	}
+	switch warning.Kind {
+	case ingest.WarningBitrateDrop:
+		bitrate, baseline := math.Round(warning.BitrateKbps), math.Round(warning.BaselineKbps)
+		payload.BitrateKbps = &bitrate
+		payload.BaselineKbps = &baseline
+		payload.Message = fmt.Sprintf("Bitrate dropped to %.0f kbps (was %.0f kbps)", bitrate, baseline)
+	case ingest.WarningReconnects:
+		minutes := int(warning.Window / time.Minute)
+		payload.Connections = &warning.Connections
+		payload.WindowMinutes = &minutes
+		payload.Message = fmt.Sprintf("The stream reconnected %d times in %d minutes", warning.Connections-1, minutes)
+	}
+	h.logger.Warnf("Ingest warning: %s", payload.Message)
+
+	data, err := websocket.EncodeMessage(websocket.TypeIngestWarning, payload)
+	if err != nil {
+		h.logger.Errorf("Failed to encode ingest warning: %v", err)
+		return
+	}
+
+	currentNext, err := h.db.GetCurrentNextDJ()
+	if err != nil {
+		// Admins are still told
+		h.logger.Errorf("Failed to get current DJ: %v", err)
+	}
+	h.djConns.send(data, func(c *djConn) bool {
+		if c.reservation == nil {
+			return true
+		}
+		return currentNext != nil && currentNext.CurrentID != nil && *currentNext.CurrentID == c.reservation.ID
+	})
+}
+
+func roundKbps(kbps *float64) *float32 {
+	if kbps == nil {
+		return nil
+	}
+	rounded := float32(math.Round(*kbps*10) / 10)
+	return &rounded
+}
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	maxBanReasonLength       = 500
+	maxBanViewerIDLength     = 64
+	defaultModerationActions = 100
+	maxModerationActions     = 500
+)
+
+// DeleteChatMessage removes a message from live chat and the stored chat log
+func (h *Handler) DeleteChatMessage(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireModerator(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "messageId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid message ID")
+		return
+	}
+
+	if err := h.wsManager.DeleteChatMessage(moderator, id); err != nil {
+		if errors.Is(err, websocket.ErrMessageNotFound) {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Message not found")
+			return
+		}
+		h.logger.Errorf("Failed to delete chat message: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to delete chat message")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetChatBans(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireModerator(w, r); !ok {
+		return
+	}
+
+	bans := h.wsManager.ActiveBans()
+	apiBans := make([]ChatBan, len(bans))
+	for i, ban := range bans {
+		apiBans[i] = toAPIChatBan(ban)
+	}
+	// Active bans come from a map, keep the response stable
+	sortChatBans(apiBans)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiBans)
+}
+
+// CreateChatBan bans or times out a viewer
+func (h *Handler) CreateChatBan(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireModerator(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateChatBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	target := websocket.BanTarget{
+		IncludeIP: req.IncludeIp != nil && *req.IncludeIp,
+	}
+	if req.MessageId != nil {
+		messageID := uuid.UUID(*req.MessageId)
+		target.MessageID = &messageID
+	} else {
+		if req.ViewerId != nil && strings.TrimSpace(*req.ViewerId) != "" {
+			// Viewer IDs are UUIDs issued with the viewer token
+			viewerID, err := uuid.Parse(strings.TrimSpace(*req.ViewerId))
+			if err != nil || len(*req.ViewerId) > maxBanViewerIDLength {
+				h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "viewerId must be a viewer ID")
+				return
+			}
+			target.ViewerID = viewerID.String()
+		}
+		if req.Ip != nil && strings.TrimSpace(*req.Ip) != "" {
+			ip := net.ParseIP(strings.TrimSpace(*req.Ip))
+			if ip == nil {
+				h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "ip must be an IP address")
+				return
+			}
+			target.IP = ip.String()
+		}
+	}
+
+	duration := time.Duration(0)
+	if req.DurationSeconds != nil {
+		if *req.DurationSeconds < 0 || *req.DurationSeconds > websocket.MaxTimeoutSeconds {
+			h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("durationSeconds must be between 0 and %d", websocket.MaxTimeoutSeconds))
+			return
+		}
+		duration = time.Duration(*req.DurationSeconds) * time.Second
+	}
+
+	reason := ""
+	if req.Reason != nil {
+		reason = strings.TrimSpace(*req.Reason)
+	}
+	if utf8.RuneCountInString(reason) > maxBanReasonLength {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("reason must be at most %d characters", maxBanReasonLength))
+		return
+	}
+
+	ban, err := h.wsManager.BanViewer(moderator, target, duration, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, websocket.ErrMessageNotFound):
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Message not found")
+		case errors.Is(err, websocket.ErrEmptyBanTarget):
+			h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "messageId, viewerId or ip is required")
+		case errors.Is(err, websocket.ErrBanNotSaved):
+			h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to save ban")
+		default:
+			h.logger.Errorf("Failed to ban viewer: %v", err)
+			h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to ban viewer")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(toAPIChatBan(ban))
+}
+
+func (h *Handler) DeleteChatBan(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireModerator(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "banId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid ban ID")
+		return
+	}
+
+	if err := h.wsManager.Unban(moderator, id); err != nil {
+		if errors.Is(err, websocket.ErrBanNotFound) {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Ban not found")
+			return
+		}
+		h.logger.Errorf("Failed to lift ban: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to lift ban")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetChatSettings(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireModerator(w, r); !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toAPIChatSettings(h.wsManager.ChatSettings()))
+}
+
+func (h *Handler) UpdateChatSettings(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.requireModerator(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChatSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.SlowModeSeconds < 0 {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "slowModeSeconds must not be negative")
+		return
+	}
+
+	settings := websocket.ChatSettings{
+		SlowMode:       time.Duration(req.SlowModeSeconds) * time.Second,
+		SubscriberOnly: req.SubscriberOnly,
+	}
+	h.wsManager.SetChatSettings(moderator, settings)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toAPIChatSettings(settings))
+}
+
+// GetModerationActions returns the audit log, newest first
+func (h *Handler) GetModerationActions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireModerator(w, r); !ok {
+		return
+	}
+
+	limit := defaultModerationActions
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > maxModerationActions {
+			h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("limit must be between 1 and %d", maxModerationActions))
+			return
+		}
+		limit = parsed
+	}
+
+	actions, err := h.db.GetModerationActions(limit)
+	if err != nil {
+		h.logger.Errorf("Failed to get moderation actions: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get moderation actions")
+		return
+	}
+
+	apiActions := make([]ModerationAction, len(actions))
+	for i, action := range actions {
+		apiActions[i] = ModerationAction{
+			Id:             openapi_types.UUID(action.ID),
+			Action:         ModerationActionAction(action.Action),
+			Moderator:      action.Moderator,
+			MessageId:      action.MessageID,
+			BanId:          action.BanID,
+			TargetViewerId: action.TargetViewerID,
+			TargetIp:       action.TargetIP,
+			CreatedAt:      action.CreatedAt,
+		}
+		if action.Details != "" {
+			apiActions[i].Details = &action.Details
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiActions)
+}
+
+// requireModerator checks the "Authorization: Bearer <token>" header against
+// the configured moderator tokens. An error response has already been
+// written when it returns false.
+func (h *Handler) requireModerator(w http.ResponseWriter, r *http.Request) (string, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Moderator token required")
+		return "", false
+	}
+
+	name, ok := h.wsManager.AuthenticateModerator(strings.TrimSpace(token))
+	if !ok {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid moderator token")
+		return "", false
+	}
+	return name, true
+}
+
+// saveModerationAction persists bans and writes the audit log for actions
+// taken over WebSocket or REST
+func (h *Handler) saveModerationAction(action websocket.ModerationAction) error {
+	record := &db.ModerationAction{
+		Action:    action.Action,
+		Moderator: action.Moderator,
+		MessageID: action.MessageID,
+		CreatedAt: action.CreatedAt,
+	}
+
+	if ban := action.Ban; ban != nil {
+		record.BanID = &ban.ID
+		record.TargetViewerID = optionalString(ban.ViewerID)
+		record.TargetIP = optionalString(ban.IP)
+	}
+
+	switch action.Action {
+	case websocket.ActionDeleteMessage:
+		if err := h.db.MarkChatMessageDeleted(*action.MessageID); err != nil {
+			h.logger.Errorf("Failed to mark chat message deleted: %v", err)
+		}
+
+	case websocket.ActionBan, websocket.ActionTimeout:
+		ban := action.Ban
+		record.Details = ban.Reason
+		if ban.ExpiresAt != nil {
+			record.Details = strings.TrimSpace(fmt.Sprintf("until %s %s", ban.ExpiresAt.UTC().Format(time.RFC3339), ban.Reason))
+		}
+		// The ban and its audit entry are saved together
+		return h.db.CreateChatBan(&db.ChatBan{
+			ID:        ban.ID,
+			ViewerID:  optionalString(ban.ViewerID),
+			IP:        optionalString(ban.IP),
+			Reason:    ban.Reason,
+			ExpiresAt: ban.ExpiresAt,
+			CreatedBy: ban.CreatedBy,
+			CreatedAt: ban.CreatedAt,
+		}, record)
+
+	case websocket.ActionUnban:
+		if err := h.db.RevokeChatBan(action.Ban.ID); err != nil {
+			h.logger.Errorf("Failed to revoke chat ban: %v", err)
+		}
+
+	case websocket.ActionChatSettings:
+		record.Details = fmt.Sprintf("slowModeSeconds=%d subscriberOnly=%t",
+			int(action.Settings.SlowMode/time.Second), action.Settings.SubscriberOnly)
+	}
+
+	return h.db.InsertModerationAction(record)
+}
+
+// lookupChatMessage finds messages that have left the in-memory history
+func (h *Handler) lookupChatMessage(id uuid.UUID) (websocket.ChatMessage, bool) {
+	msg, err := h.db.GetChatMessage(id)
+	if err != nil {
+		if err.Error() != "message not found" {
+			h.logger.Errorf("Failed to look up chat message: %v", err)
+		}
+		return websocket.ChatMessage{}, false
+	}
+	if msg.DeletedAt != nil {
+		return websocket.ChatMessage{}, false
+	}
+
+	result := websocket.ChatMessage{
+		ID:       msg.ID,
+		ViewerID: msg.ViewerID,
+		Nickname: msg.Nickname,
+		Body:     msg.Body,
+		SentAt:   msg.CreatedAt,
+	}
+	if msg.IP != nil {
+		result.IP = *msg.IP
+	}
+	return result, true
+}
+
+// loadChatBans restores bans that were active before a restart
+func (h *Handler) loadChatBans() {
+	bans, err := h.db.GetActiveChatBans()
+	if err != nil {
+		h.logger.Errorf("Failed to load chat bans: %v", err)
+		return
+	}
+
+	restored := make([]websocket.Ban, len(bans))
+	for i, ban := range bans {
+		restored[i] = websocket.Ban{
+			ID:        ban.ID,
+			Reason:    ban.Reason,
+			ExpiresAt: ban.ExpiresAt,
+			CreatedBy: ban.CreatedBy,
+			CreatedAt: ban.CreatedAt,
+		}
+		if ban.ViewerID != nil {
+			restored[i].ViewerID = *ban.ViewerID
+		}
+		if ban.IP != nil {
+			restored[i].IP = *ban.IP
+		}
+	}
+	h.wsManager.LoadBans(restored)
+}
+
+func toAPIChatBan(ban websocket.Ban) ChatBan {
+	return ChatBan{
+		Id:        openapi_types.UUID(ban.ID),
+		ViewerId:  optionalString(ban.ViewerID),
+		Ip:        optionalString(ban.IP),
+		Reason:    ban.Reason,
+		ExpiresAt: ban.ExpiresAt,
+		CreatedBy: ban.CreatedBy,
+		CreatedAt: ban.CreatedAt,
+	}
+}
+
+func sortChatBans(bans []ChatBan) {
+	slices.SortFunc(bans, func(a, b ChatBan) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+func toAPIChatSettings(settings websocket.ChatSettings) ChatSettings {
+	return ChatSettings{
+		SlowModeSeconds: int(settings.SlowMode / time.Second),
+		SubscriberOnly:  settings.SubscriberOnly,
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
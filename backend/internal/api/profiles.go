@@ -0,0 +1,365 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Profile field limits, mirrored in api/openapi.yaml
+const (
+	maxBioLength    = 1000
+	maxGenres       = 10
+	maxGenreLength  = 30
+	maxLinks        = 5
+	maxLinkLength   = 500
+	avatarDirectory = "avatars"
+)
+
+// avatarExtensions maps the accepted sniffed image types to file extensions
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+func (h *Handler) CreateDJProfile(w http.ResponseWriter, r *http.Request) {
+	var req DjProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	bio, genres, links, ok := h.validateProfile(w, req)
+	if !ok {
+		return
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		h.logger.Errorf("Failed to generate profile token: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create profile")
+		return
+	}
+
+	profile, err := h.db.CreateDJProfile(req.DisplayName, bio, genres, links, tokenHash)
+	if err != nil {
+		h.logger.Errorf("Failed to create profile: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to create profile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(CreatedDjProfile{
+		Profile: h.toAPIDJProfile(*profile),
+		Token:   token,
+	})
+}
+
+func (h *Handler) GetDJProfile(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.findProfile(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.toAPIDJProfile(*profile))
+}
+
+func (h *Handler) UpdateDJProfile(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.authenticateProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var req DjProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	bio, genres, links, ok := h.validateProfile(w, req)
+	if !ok {
+		return
+	}
+
+	updated, err := h.db.UpdateDJProfile(profile.ID, profile.TokenHash, req.DisplayName, bio, genres, links)
+	if err != nil {
+		h.sendProfileError(w, err, "Failed to update profile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.toAPIDJProfile(*updated))
+}
+
+// UploadDJProfileAvatar stores the raw image in the request body under the
+// media directory, replacing any previous avatar
+func (h *Handler) UploadDJProfileAvatar(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.authenticateProfile(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.config.Media.AvatarMaxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.sendError(w, http.StatusRequestEntityTooLarge, "INVALID_AVATAR", fmt.Sprintf("Avatar must be at most %d KB", h.config.Media.AvatarMaxBytes/1024))
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+		return
+	}
+
+	ext, ok := avatarExtensions[http.DetectContentType(data)]
+	if !ok {
+		h.sendError(w, http.StatusBadRequest, "INVALID_AVATAR", "Avatar must be a JPEG, PNG, GIF or WebP image")
+		return
+	}
+
+	file := filepath.Join(avatarDirectory, profile.ID.String()+ext)
+	if err := h.writeMediaFile(file, data); err != nil {
+		h.logger.Errorf("Failed to store avatar: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store avatar")
+		return
+	}
+
+	updated, err := h.db.SetDJProfileAvatar(profile.ID, profile.TokenHash, file)
+	if err != nil {
+		h.sendProfileError(w, err, "Failed to store avatar")
+		return
+	}
+
+	// An upload in another format leaves the old file behind
+	if profile.AvatarFile != nil && *profile.AvatarFile != file {
+		if err := os.Remove(filepath.Join(h.config.Media.Dir, *profile.AvatarFile)); err != nil && !os.IsNotExist(err) {
+			h.logger.Warnf("Failed to remove old avatar: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.toAPIDJProfile(*updated))
+}
+
+func (h *Handler) GetDJProfileAvatar(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.findProfile(w, r)
+	if !ok {
+		return
+	}
+	if profile.AvatarFile == nil {
+		h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Profile has no avatar")
+		return
+	}
+
+	file, err := os.Open(filepath.Join(h.config.Media.Dir, *profile.AvatarFile))
+	if err != nil {
+		h.logger.Errorf("Failed to open avatar: %v", err)
+		h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Profile has no avatar")
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		h.logger.Errorf("Failed to stat avatar: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to read avatar")
+		return
+	}
+
+	// avatarUrl carries a version, so a day of caching is safe
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// writeMediaFile atomically writes a file below the media directory
+func (h *Handler) writeMediaFile(name string, data []byte) error {
+	path := filepath.Join(h.config.Media.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create media directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// findProfile loads the profile named by the profileId URL parameter. An
+// error response has already been written when it returns false.
+func (h *Handler) findProfile(w http.ResponseWriter, r *http.Request) (*db.DJProfile, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "profileId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid profile ID")
+		return nil, false
+	}
+
+	profile, err := h.db.GetDJProfile(id)
+	if err != nil {
+		h.sendProfileError(w, err, "Failed to get profile")
+		return nil, false
+	}
+	return profile, true
+}
+
+// authenticateProfile loads the profile named by the profileId URL parameter
+// and checks the X-Profile-Token header
+func (h *Handler) authenticateProfile(w http.ResponseWriter, r *http.Request) (*db.DJProfile, bool) {
+	token := r.Header.Get("X-Profile-Token")
+	if token == "" {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Profile token is required")
+		return nil, false
+	}
+
+	profile, ok := h.findProfile(w, r)
+	if !ok {
+		return nil, false
+	}
+	if profile.TokenHash != hashSecretToken(token) {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid profile token")
+		return nil, false
+	}
+	return profile, true
+}
+
+func (h *Handler) sendProfileError(w http.ResponseWriter, err error, message string) {
+	switch err.Error() {
+	case "profile not found":
+		h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Profile not found")
+	case "invalid token":
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid profile token")
+	default:
+		h.logger.Errorf("%s: %v", message, err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", message)
+	}
+}
+
+// resolveProfileToken returns the profile a reservation performer attaches
+// with profileToken, or nil if no token was given. An error response has
+// already been written when it returns false.
+func (h *Handler) resolveProfileToken(w http.ResponseWriter, token *string) (*db.DJProfile, bool) {
+	if token == nil || *token == "" {
+		return nil, true
+	}
+
+	profile, err := h.db.GetDJProfileByToken(hashSecretToken(*token))
+	if err != nil {
+		if err.Error() == "profile not found" {
+			h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE_TOKEN", "Unknown profile token")
+			return nil, false
+		}
+		h.logger.Errorf("Failed to get profile: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get profile")
+		return nil, false
+	}
+	return profile, true
+}
+
+// validateProfile checks the profile fields and returns them normalized,
+// replying with an error if they are invalid
+func (h *Handler) validateProfile(w http.ResponseWriter, req DjProfileInput) (string, []string, []string, bool) {
+	if !h.validateDJName(w, req.DisplayName) {
+		return "", nil, nil, false
+	}
+
+	var bio string
+	if req.Bio != nil {
+		bio = strings.TrimSpace(*req.Bio)
+	}
+	if utf8.RuneCountInString(bio) > maxBioLength {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("Bio must be at most %d characters", maxBioLength))
+		return "", nil, nil, false
+	}
+
+	genres := []string{}
+	if req.Genres != nil {
+		if len(*req.Genres) > maxGenres {
+			h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("At most %d genres are allowed", maxGenres))
+			return "", nil, nil, false
+		}
+		for _, genre := range *req.Genres {
+			genre = strings.TrimSpace(genre)
+			if genre == "" || utf8.RuneCountInString(genre) > maxGenreLength {
+				h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("Genres must have 1-%d characters", maxGenreLength))
+				return "", nil, nil, false
+			}
+			genres = append(genres, genre)
+		}
+	}
+
+	links := []string{}
+	if req.Links != nil {
+		if len(*req.Links) > maxLinks {
+			h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("At most %d links are allowed", maxLinks))
+			return "", nil, nil, false
+		}
+		for _, link := range *req.Links {
+			link = strings.TrimSpace(link)
+			if !validHTTPURL(link, maxLinkLength) {
+				h.sendError(w, http.StatusBadRequest, "INVALID_PROFILE", fmt.Sprintf("Links must be absolute http(s) URLs of at most %d characters", maxLinkLength))
+				return "", nil, nil, false
+			}
+			links = append(links, link)
+		}
+	}
+
+	return bio, genres, links, true
+}
+
+func (h *Handler) toAPIDJProfile(profile db.DJProfile) DjProfile {
+	apiProfile := DjProfile{
+		Id:          openapi_types.UUID(profile.ID),
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		Genres:      []string(profile.Genres),
+		Links:       []string(profile.Links),
+		CreatedAt:   profile.CreatedAt,
+		UpdatedAt:   profile.UpdatedAt,
+	}
+	if apiProfile.Genres == nil {
+		apiProfile.Genres = []string{}
+	}
+	if apiProfile.Links == nil {
+		apiProfile.Links = []string{}
+	}
+	if profile.AvatarFile != nil {
+		// The version busts caches when a new avatar is uploaded
+		avatarURL := fmt.Sprintf("%s/api/v1/dj-profiles/%s/avatar?v=%d", h.config.PublicURL, profile.ID, profile.UpdatedAt.Unix())
+		apiProfile.AvatarUrl = &avatarURL
+	}
+	return apiProfile
+}
+
+func profileID(profile *db.DJProfile) *uuid.UUID {
+	if profile == nil {
+		return nil
+	}
+	return &profile.ID
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetReactionStats returns per-minute reaction totals recorded during a reservation
+func (h *Handler) GetReactionStats(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return
+	}
+
+	if _, err := h.db.GetReservation(id); err != nil {
+		if err.Error() == "reservation not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Reservation not found")
+			return
+		}
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get reaction stats")
+		return
+	}
+
+	stats, err := h.db.GetReactionStats(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get reaction stats: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get reaction stats")
+		return
+	}
+
+	// Rows are ordered by minute, so group consecutive rows
+	minutes := []ReactionMinute{}
+	for _, stat := range stats {
+		if len(minutes) == 0 || !minutes[len(minutes)-1].Minute.Equal(stat.Minute) {
+			minutes = append(minutes, ReactionMinute{
+				Minute: stat.Minute,
+				Counts: map[string]int{},
+			})
+		}
+		current := &minutes[len(minutes)-1]
+		current.Counts[stat.Reaction] = stat.Count
+		current.Total += stat.Count
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(minutes)
+}
+
+func (h *Handler) saveReactionTotals(minute time.Time, counts map[string]int) {
+	if err := h.db.AddReactionTotals(minute, counts); err != nil {
+		h.logger.Errorf("Failed to save reaction totals: %v", err)
+	}
+}
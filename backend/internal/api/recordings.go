@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// GetRecordings lists the segments recorded during a reservation
+func (h *Handler) GetRecordings(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return
+	}
+
+	if _, err := h.db.GetReservation(id); err != nil {
+		if err.Error() == "reservation not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Reservation not found")
+			return
+		}
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get recordings")
+		return
+	}
+
+	recordings, err := h.db.GetRecordings(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get recordings: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get recordings")
+		return
+	}
+
+	apiRecordings := make([]Recording, len(recordings))
+	for i, recording := range recordings {
+		apiRecordings[i] = toAPIRecording(recording)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiRecordings)
+}
+
+// RecordingSegmentCreated is called by the MediaMTX runOnRecordSegmentCreate
+// hook with the form fields path and segment. Segments of sets whose DJs
+// opted out of recording are discarded instead.
+func (h *Handler) RecordingSegmentCreated(w http.ResponseWriter, r *http.Request) {
+	pathName, file, ok := h.parseSegmentHook(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if pathName == h.config.MediaMTX.PathName && !h.recordingConsented(now) {
+		h.discardSegment(file)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	recording, err := h.db.CreateRecording(pathName, file, now)
+	if err != nil {
+		h.logger.Errorf("Failed to register recording %s: %v", file, err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to register recording")
+		return
+	}
+	if recording.ReservationID == nil {
+		h.logger.Infof("Recording %s started outside any reservation", file)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordingSegmentCompleted is called by the MediaMTX
+// runOnRecordSegmentComplete hook with the form fields path, segment and
+// duration (seconds)
+func (h *Handler) RecordingSegmentCompleted(w http.ResponseWriter, r *http.Request) {
+	pathName, file, ok := h.parseSegmentHook(w, r)
+	if !ok {
+		return
+	}
+
+	duration, ok := parseSegmentDuration(r.Form.Get("duration"))
+	if !ok {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid segment duration")
+		return
+	}
+
+	var size *int64
+	info, statErr := os.Stat(filepath.Join(h.config.Media.Dir, file))
+	if statErr == nil {
+		bytes := info.Size()
+		size = &bytes
+	}
+
+	now := time.Now()
+	_, err := h.db.CompleteRecording(file, duration.Seconds(), size, now)
+	if err != nil && err.Error() == "recording not found" {
+		startedAt := now.Add(-duration)
+		if pathName == h.config.MediaMTX.PathName && !h.recordingConsented(startedAt) {
+			h.discardSegment(file)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if errors.Is(statErr, os.ErrNotExist) {
+			// The create hook removed it, e.g. because consent could not
+			// be confirmed
+			h.logger.Infof("Recording %s was discarded before completion", file)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// The create hook was missed (e.g. during a backend restart)
+		if _, err = h.db.CreateRecording(pathName, file, startedAt); err == nil {
+			_, err = h.db.CompleteRecording(file, duration.Seconds(), size, now)
+		}
+	}
+	if statErr != nil {
+		h.logger.Warnf("Failed to stat recording %s: %v", file, statErr)
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to complete recording %s: %v", file, err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to complete recording")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSegmentHook reads the path and segment fields of a recording hook and
+// maps the segment path inside the MediaMTX container to a file relative to
+// the media directory
+func (h *Handler) parseSegmentHook(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	if err := r.ParseForm(); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid form data")
+		return "", "", false
+	}
+
+	pathName := r.Form.Get("path")
+	segment := r.Form.Get("segment")
+	if pathName == "" || segment == "" {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "path and segment are required")
+		return "", "", false
+	}
+
+	file, err := filepath.Rel(h.config.MediaMTX.MediaDir, segment)
+	if err != nil || !filepath.IsAbs(segment) || file == "." || strings.HasPrefix(file, "..") {
+		h.logger.Warnf("Recording segment %s is outside the media directory %s", segment, h.config.MediaMTX.MediaDir)
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Segment is outside the media directory")
+		return "", "", false
+	}
+
+	return pathName, file, true
+}
+
+// parseSegmentDuration accepts MTX_SEGMENT_DURATION as seconds or as a Go
+// duration string
+func parseSegmentDuration(value string) (time.Duration, bool) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return duration, true
+	}
+	return 0, false
+}
+
+func toAPIRecording(recording db.Recording) Recording {
+	apiRecording := Recording{
+		Id:              openapi_types.UUID(recording.ID),
+		SessionId:       openapi_types.UUID(recording.SessionID),
+		StartedAt:       recording.StartedAt,
+		Status:          RecordingStatusRecording,
+		DurationSeconds: recording.DurationSeconds,
+		SizeBytes:       recording.SizeBytes,
+	}
+	if recording.CompletedAt != nil && recording.DurationSeconds != nil {
+		apiRecording.Status = RecordingStatusComplete
+		endedAt := recording.StartedAt.Add(time.Duration(*recording.DurationSeconds * float64(time.Second)))
+		apiRecording.EndedAt = &endedAt
+	}
+	return apiRecording
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// recoveryRequestInterval limits how often a recovery mail can be sent for the same reservation
+const recoveryRequestInterval = 5 * time.Minute
+
+func (h *Handler) RequestPasscodeRecovery(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return
+	}
+
+	if !h.mailer.Enabled() {
+		h.sendError(w, http.StatusServiceUnavailable, "RECOVERY_UNAVAILABLE", "Passcode recovery is not available")
+		return
+	}
+
+	reservation, err := h.db.GetReservation(id)
+	if err != nil {
+		if err.Error() == "reservation not found" {
+			// Respond the same way as for reservations without an email
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to request passcode recovery")
+		return
+	}
+
+	if reservation.ContactEmail == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// From here on every outcome is answered with 202, so that failures do not
+	// reveal that the reservation has a contact email
+	w.WriteHeader(http.StatusAccepted)
+
+	recent, err := h.db.HasRecentRecoveryToken(id, time.Now().Add(-recoveryRequestInterval))
+	if err != nil {
+		h.logger.Errorf("Failed to check recovery tokens: %v", err)
+		return
+	}
+	if recent {
+		return
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		h.logger.Errorf("Failed to generate recovery token: %v", err)
+		return
+	}
+
+	expiresAt := time.Now().Add(h.config.Passcode.RecoveryTTL)
+	if err := h.db.CreatePasscodeRecoveryToken(id, tokenHash, expiresAt); err != nil {
+		h.logger.Errorf("Failed to store recovery token: %v", err)
+		return
+	}
+
+	if err := h.mailer.Send(*reservation.ContactEmail, "DJ予約の暗証番号再設定", h.buildRecoveryMail(reservation.DJName, id, token, expiresAt)); err != nil {
+		h.logger.Errorf("Failed to send recovery mail for reservation %s: %v", id, err)
+		return
+	}
+
+	h.logger.Infof("Sent passcode recovery mail for reservation %s", id)
+}
+
+func (h *Handler) ResetPasscode(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return
+	}
+
+	var req ResetPasscodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if msg := h.validatePasscode(req.NewPasscode); msg != "" {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PASSCODE", msg)
+		return
+	}
+
+	if req.Token == "" {
+		h.sendError(w, http.StatusUnauthorized, "INVALID_RECOVERY_TOKEN", "Recovery token is invalid or expired")
+		return
+	}
+
+	err = h.db.ResetPasscodeWithToken(id, hashSecretToken(req.Token), req.NewPasscode)
+	if err != nil {
+		if err.Error() == "invalid recovery token" {
+			h.sendError(w, http.StatusUnauthorized, "INVALID_RECOVERY_TOKEN", "Recovery token is invalid or expired")
+			return
+		}
+		h.logger.Errorf("Failed to reset passcode: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to reset passcode")
+		return
+	}
+
+	h.logger.Infof("Passcode reset for reservation %s", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) buildRecoveryMail(djName string, reservationID uuid.UUID, token string, expiresAt time.Time) string {
+	link := fmt.Sprintf("%s/recover-passcode?reservationId=%s&token=%s",
+		h.config.PublicURL, reservationID, url.QueryEscape(token))
+
+	loc, err := time.LoadLocation(h.config.EventTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return fmt.Sprintf(`%s さん
+
+DJ予約の暗証番号再設定がリクエストされました。
+以下のリンクから新しい暗証番号を設定してください。
+
+%s
+
+このリンクは %s まで、1回のみ有効です。
+心当たりがない場合はこのメールを破棄してください。
+`, djName, link, expiresAt.In(loc).Format("2006-01-02 15:04"))
+}
+
+// newSecretToken returns a random URL-safe token and its SHA-256 hash, as
+// used for recovery links and slot holds. Only the hash is persisted.
+func newSecretToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashSecretToken(token), nil
+}
+
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
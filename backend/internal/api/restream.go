@@ -0,0 +1,325 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/dj-event/stream-system/internal/restream"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	restreamInterval = 5 * time.Second
+	// restreamClaimTTL is how long a replica's claim on a target survives
+	// without renewal, e.g. after it crashed
+	restreamClaimTTL   = 6 * restreamInterval
+	maxRestreamNameLen = 100
+)
+
+// restreamSchemes are the destinations ffmpeg can push to
+var restreamSchemes = map[string]bool{"rtmp": true, "rtmps": true, "srt": true}
+
+// GetRestreamTargets lists the restream targets with their status
+func (h *Handler) GetRestreamTargets(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.requireAdmin(w, r); !ok {
+		return
+	}
+
+	targets, err := h.db.GetRestreamTargets()
+	if err != nil {
+		h.logger.Errorf("Failed to get restream targets: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get restream targets")
+		return
+	}
+
+	apiTargets := make([]RestreamTarget, len(targets))
+	for i, target := range targets {
+		apiTargets[i] = toAPIRestreamTarget(target)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiTargets)
+}
+
+// CreateRestreamTarget adds an RTMP destination
+func (h *Handler) CreateRestreamTarget(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateRestreamTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxRestreamNameLen {
+		h.sendError(w, http.StatusBadRequest, "INVALID_RESTREAM_TARGET", "Name must be 1 to 100 characters")
+		return
+	}
+	targetURL := strings.TrimSpace(req.Url)
+	if parsed, err := url.Parse(targetURL); err != nil || !restreamSchemes[parsed.Scheme] || parsed.Host == "" {
+		h.sendError(w, http.StatusBadRequest, "INVALID_RESTREAM_TARGET", "URL must be an rtmp://, rtmps:// or srt:// address")
+		return
+	}
+
+	target, err := h.db.CreateRestreamTarget(name, targetURL, req.Enabled != nil && *req.Enabled)
+	if err != nil {
+		h.logger.Errorf("Failed to create restream target: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to create restream target")
+		return
+	}
+	h.logger.Infof("Admin %s added restream target %s (%s)", admin, target.ID, target.Name)
+	h.wakeRestreams()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(toAPIRestreamTarget(*target))
+}
+
+// DeleteRestreamTarget removes a destination
+func (h *Handler) DeleteRestreamTarget(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.restreamTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteRestreamTarget(id); err != nil {
+		if err.Error() == "restream target not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Restream target not found")
+			return
+		}
+		h.logger.Errorf("Failed to delete restream target: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to delete restream target")
+		return
+	}
+	h.logger.Infof("Admin %s removed restream target %s", admin, id)
+	h.wakeRestreams()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartRestreamTarget enables forwarding to a destination
+func (h *Handler) StartRestreamTarget(w http.ResponseWriter, r *http.Request) {
+	h.setRestreamTargetEnabled(w, r, true)
+}
+
+// StopRestreamTarget disables forwarding to a destination
+func (h *Handler) StopRestreamTarget(w http.ResponseWriter, r *http.Request) {
+	h.setRestreamTargetEnabled(w, r, false)
+}
+
+func (h *Handler) setRestreamTargetEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	admin, ok := h.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.restreamTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	target, err := h.db.SetRestreamTargetEnabled(id, enabled)
+	if err != nil {
+		if err.Error() == "restream target not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Restream target not found")
+			return
+		}
+		h.logger.Errorf("Failed to update restream target: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to update restream target")
+		return
+	}
+	action := "stopped"
+	if enabled {
+		action = "started"
+	}
+	h.logger.Infof("Admin %s %s restream target %s (%s)", admin, action, target.ID, target.Name)
+	h.wakeRestreams()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toAPIRestreamTarget(*target))
+}
+
+func (h *Handler) restreamTargetID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "targetId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid restream target ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// StreamReady is called by the MediaMTX runOnReady hook with the form field
+// path, so that forwarding starts without waiting for the next poll
+func (h *Handler) StreamReady(w http.ResponseWriter, r *http.Request) {
+	h.streamStateHook(w, r)
+}
+
+// StreamNotReady is called by the MediaMTX runOnNotReady hook
+func (h *Handler) StreamNotReady(w http.ResponseWriter, r *http.Request) {
+	h.streamStateHook(w, r)
+}
+
+func (h *Handler) streamStateHook(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid form data")
+		return
+	}
+	if r.Form.Get("path") == h.config.MediaMTX.PathName {
+		h.wakeRestreams()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// wakeRestreams reconciles the forwarders of this replica right away
+func (h *Handler) wakeRestreams() {
+	select {
+	case h.restreamWake <- struct{}{}:
+	default:
+	}
+}
+
+// superviseRestreams keeps a forwarder running for every enabled target
+// while a DJ is live. Every replica reconciles, but a target is only
+// forwarded by the replica holding its claim.
+func (h *Handler) superviseRestreams() {
+	ticker := time.NewTicker(restreamInterval)
+	defer ticker.Stop()
+
+	for {
+		h.reconcileRestreams(time.Now())
+		select {
+		case <-ticker.C:
+		case <-h.restreamWake:
+		}
+	}
+}
+
+func (h *Handler) reconcileRestreams(now time.Time) {
+	targets, err := h.db.GetRestreamTargets()
+	if err != nil {
+		h.logger.Warnf("Failed to get restream targets: %v", err)
+		return
+	}
+
+	replica := h.config.Cluster.ReplicaID
+	// A set whose DJs opted out of restreaming is treated like no stream
+	live := h.restreamSourceLive() && h.restreamConsented(now)
+
+	var wanted []restream.Target
+	claimed := make(map[uuid.UUID]bool)
+	for _, target := range targets {
+		if !target.Enabled || !live {
+			continue
+		}
+		ok, err := h.db.ClaimRestreamTarget(target.ID, replica, now, now.Add(-restreamClaimTTL))
+		if err != nil {
+			h.logger.Warnf("Failed to claim restream target %s: %v", target.ID, err)
+			continue
+		}
+		if ok {
+			claimed[target.ID] = true
+			wanted = append(wanted, restream.Target{ID: target.ID, URL: target.URL})
+		}
+	}
+
+	h.restream.Reconcile(wanted)
+
+	for _, target := range targets {
+		if claimed[target.ID] {
+			continue
+		}
+		if target.Owner != nil && *target.Owner == replica {
+			if err := h.db.ReleaseRestreamTarget(target.ID, replica); err != nil {
+				h.logger.Warnf("Failed to release restream target %s: %v", target.ID, err)
+			}
+		}
+
+		status := db.RestreamIdle
+		switch {
+		case !target.Enabled:
+			status = db.RestreamStopped
+		case live:
+			// Another replica forwards it
+			continue
+		}
+		if target.Status != status {
+			h.updateRestreamStatus(target.ID, status, false, nil)
+		}
+	}
+}
+
+// restreamSourceLive reports whether a DJ is publishing
+func (h *Handler) restreamSourceLive() bool {
+	if h.mediamtx == nil {
+		return h.checkStreamIsLive()
+	}
+	path, err := h.mediamtx.GetPath(h.config.MediaMTX.PathName)
+	if err != nil {
+		if !errors.Is(err, mediamtx.ErrNotFound) {
+			h.logger.Debugf("Failed to get MediaMTX path: %v", err)
+		}
+		return false
+	}
+	return path.Ready
+}
+
+// onRestreamEvent records the forwarder state reported by the supervisor
+func (h *Handler) onRestreamEvent(event restream.Event) {
+	switch event.Kind {
+	case restream.EventStarted:
+		h.updateRestreamStatus(event.TargetID, db.RestreamRunning, false, nil)
+	case restream.EventRestarted:
+		h.updateRestreamStatus(event.TargetID, db.RestreamRunning, true, nil)
+	case restream.EventExited:
+		message := event.Err.Error()
+		h.updateRestreamStatus(event.TargetID, db.RestreamRestarting, false, &message)
+	}
+}
+
+func (h *Handler) updateRestreamStatus(id uuid.UUID, status string, restarted bool, lastError *string) {
+	if err := h.db.UpdateRestreamStatus(id, status, restarted, lastError); err != nil {
+		h.logger.Warnf("Failed to update restream target %s: %v", id, err)
+	}
+}
+
+// StopRestreams stops this replica's forwarders and releases their claims
+// so that another replica can take over right away
+func (h *Handler) StopRestreams() {
+	running := h.restream.Running()
+	h.restream.Reconcile(nil)
+	for _, id := range running {
+		if err := h.db.ReleaseRestreamTarget(id, h.config.Cluster.ReplicaID); err != nil {
+			h.logger.Warnf("Failed to release restream target %s: %v", id, err)
+		}
+	}
+}
+
+func toAPIRestreamTarget(target db.RestreamTarget) RestreamTarget {
+	return RestreamTarget{
+		Id:           openapi_types.UUID(target.ID),
+		Name:         target.Name,
+		Url:          target.URL,
+		Enabled:      target.Enabled,
+		Status:       RestreamTargetStatus(target.Status),
+		RestartCount: target.RestartCount,
+		LastError:    target.LastError,
+		Replica:      target.Owner,
+		UpdatedAt:    target.UpdatedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/dj-event/stream-system/internal/websocket"
+)
+
+const slotEndCheckInterval = 5 * time.Second
+
+// slotEndCatchUp is how late a slot end is still acted on, e.g. after a
+// restart. Older reservations are left alone.
+const slotEndCatchUp = 5 * time.Minute
+
+// enforceSlotEnds acts on every reservation once its end time plus the
+// grace period has passed: the publisher is kicked and clients are told
+// that the slot is over
+func (h *Handler) enforceSlotEnds() {
+	ticker := time.NewTicker(slotEndCheckInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		until := now.Add(-h.config.Booking.SlotEndGrace)
+		reservations, err := h.db.ClaimEndedReservations(until.Add(-slotEndCatchUp), until)
+		if err != nil {
+			h.logger.Warnf("Failed to check ended slots: %v", err)
+			continue
+		}
+		for _, reservation := range reservations {
+			h.endSlot(reservation)
+		}
+	}
+}
+
+func (h *Handler) endSlot(reservation db.Reservation) {
+	payload := websocket.SlotEndedPayload{
+		ReservationID: reservation.ID,
+		DJName:        reservation.DJName,
+		EndTime:       reservation.EndTime,
+	}
+
+	next, err := h.db.GetNextReservation(reservation.EndTime)
+	if err != nil {
+		h.logger.Errorf("Failed to get next reservation: %v", err)
+	} else if next != nil {
+		payload.NextReservationID = &next.ID
+		payload.NextDJName = &next.DJName
+		payload.NextStartTime = &next.StartTime
+	}
+
+	if h.mediamtx != nil && h.config.Booking.KickOnSlotEnd {
+		payload.PublisherKicked = h.kickPublisher(reservation, next)
+	}
+
+	h.wsManager.Broadcast(websocket.TypeSlotEnded, payload)
+}
+
+// kickPublisher disconnects whoever publishes to the stream path if they
+// connected before the reservation ended. A publisher that connected later,
+// or within the takeover lead before the next reservation starts, is the
+// next DJ and keeps streaming: with overridePublisher the next DJ can take
+// over the path while the previous set is still on.
+func (h *Handler) kickPublisher(reservation db.Reservation, next *db.Reservation) bool {
+	path, err := h.mediamtx.GetPath(h.config.MediaMTX.PathName)
+	if err != nil {
+		if !errors.Is(err, mediamtx.ErrNotFound) {
+			h.logger.Warnf("Failed to get MediaMTX path: %v", err)
+		}
+		return false
+	}
+	if !path.Ready || path.Source == nil {
+		return false
+	}
+
+	// The connection tells when this source connected; the path's ready
+	// time may be older when the source took over from another publisher
+	connected := path.ReadyTime
+	if conn, err := h.mediamtx.GetConnection(*path.Source); err == nil && !conn.Created.IsZero() {
+		connected = &conn.Created
+	} else if err != nil {
+		h.logger.Debugf("Failed to get MediaMTX connection %s: %v", path.Source.ID, err)
+	}
+	if connected != nil {
+		if !connected.Before(reservation.EndTime) {
+			return false
+		}
+		if next != nil && !connected.Before(next.StartTime.Add(-h.config.Booking.TakeoverLead)) {
+			h.logger.Infof("Not kicking publisher %s of %s: connected at %s, taking over for %s",
+				path.Source.ID, path.Name, connected.Format(time.RFC3339), next.DJName)
+			return false
+		}
+	}
+
+	if err := h.mediamtx.Kick(*path.Source); err != nil {
+		h.logger.Errorf("Failed to kick publisher of %s after the slot of %s: %v", path.Name, reservation.DJName, err)
+		return false
+	}
+
+	h.logger.Infof("Kicked publisher %s of %s: the slot of %s ended at %s",
+		path.Source.ID, path.Name, reservation.DJName, reservation.EndTime.Format(time.RFC3339))
+	return true
+}
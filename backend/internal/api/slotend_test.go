@@ -0,0 +1,112 @@
+package api
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/config"
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/dj-event/stream-system/internal/mediamtx/mediamtxtest"
+	"github.com/sirupsen/logrus"
+)
+
+const testPathName = "stream-endpoint"
+
+func newSlotEndHandler(stub *mediamtxtest.Server) *Handler {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{}
+	cfg.MediaMTX.PathName = testPathName
+	cfg.Booking.KickOnSlotEnd = true
+	cfg.Booking.TakeoverLead = 5 * time.Minute
+
+	return &Handler{
+		logger:   logger,
+		config:   cfg,
+		mediamtx: mediamtx.NewClient(stub.URL),
+	}
+}
+
+func TestKickPublisher(t *testing.T) {
+	end := time.Date(2025, 8, 29, 21, 0, 0, 0, time.UTC)
+	ending := db.Reservation{DJName: "DJ Outgoing", StartTime: end.Add(-time.Hour), EndTime: end}
+	nextAt := func(start time.Time) *db.Reservation {
+		return &db.Reservation{DJName: "DJ Next", StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(stub *mediamtxtest.Server)
+		next    *db.Reservation
+		wantIDs []string
+	}{
+		{
+			name: "outgoing DJ still publishing",
+			setup: func(stub *mediamtxtest.Server) {
+				stub.Publish(testPathName, "rtmpConn", "outgoing", end.Add(-50*time.Minute))
+			},
+			next:    nextAt(end),
+			wantIDs: []string{"outgoing"},
+		},
+		{
+			name: "next DJ took over early",
+			setup: func(stub *mediamtxtest.Server) {
+				stub.Publish(testPathName, "rtmpConn", "outgoing", end.Add(-50*time.Minute))
+				stub.TakeOver(testPathName, "rtmpConn", "next", end.Add(-2*time.Minute))
+			},
+			next: nextAt(end),
+		},
+		{
+			name: "next DJ connected after the slot ended",
+			setup: func(stub *mediamtxtest.Server) {
+				stub.Publish(testPathName, "srtConn", "next", end.Add(10*time.Second))
+			},
+			next: nextAt(end),
+		},
+		{
+			name: "no next reservation",
+			setup: func(stub *mediamtxtest.Server) {
+				stub.TakeOver(testPathName, "rtmpConn", "outgoing", end.Add(-2*time.Minute))
+			},
+			wantIDs: []string{"outgoing"},
+		},
+		{
+			name: "outgoing DJ reconnected with a gap before the next slot",
+			setup: func(stub *mediamtxtest.Server) {
+				stub.Publish(testPathName, "rtmpConn", "outgoing", end.Add(-2*time.Minute))
+			},
+			next:    nextAt(end.Add(30 * time.Minute)),
+			wantIDs: []string{"outgoing"},
+		},
+		{
+			name:  "nobody publishing",
+			setup: func(stub *mediamtxtest.Server) {},
+			next:  nextAt(end),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := mediamtxtest.NewServer()
+			defer stub.Close()
+			tt.setup(stub)
+
+			h := newSlotEndHandler(stub)
+			kicked := h.kickPublisher(ending, tt.next)
+
+			var gotIDs []string
+			for _, source := range stub.Kicked() {
+				gotIDs = append(gotIDs, source.ID)
+			}
+			if kicked != (len(tt.wantIDs) > 0) {
+				t.Errorf("kickPublisher() = %t, want %t", kicked, len(tt.wantIDs) > 0)
+			}
+			if len(gotIDs) != len(tt.wantIDs) || (len(gotIDs) > 0 && gotIDs[0] != tt.wantIDs[0]) {
+				t.Errorf("kicked sources %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
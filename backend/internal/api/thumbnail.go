@@ -0,0 +1,348 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/restream"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// maxThumbnailBytes caps the output of the thumbnail command
+	maxThumbnailBytes = 5 << 20
+	// thumbnailMaxAge is how many intervals a snapshot stays valid when the
+	// command keeps failing
+	thumbnailMaxAge = 3
+	// placeholderMaxAge lets clients notice quickly that a stream started
+	placeholderMaxAge = 60
+	placeholderWidth  = 640
+	placeholderHeight = 360
+	placeholderMargin = 32
+)
+
+// Font sizes tried in order until the text fits the placeholder
+var (
+	placeholderNameSizes     = []float64{64, 52, 40, 32, 24}
+	placeholderBrandingSizes = []float64{22, 18}
+)
+
+var errThumbnailTooLarge = errors.New("thumbnail exceeds 5 MB")
+
+// thumbnail is the latest snapshot of the live stream
+type thumbnail struct {
+	data        []byte
+	etag        string
+	generatedAt time.Time
+}
+
+// placeholder is the offline thumbnail. The card is only rendered again
+// when the DJ name or the branding changes.
+type placeholder struct {
+	djName   string
+	branding string
+	// avatarFile is served instead of the card when set
+	avatarFile string
+	thumbnail
+}
+
+// generateThumbnails runs the thumbnail command while the stream is live
+// and keeps the placeholder for the current or next DJ up to date. Each
+// replica keeps its own images.
+func (h *Handler) generateThumbnails() {
+	ticker := time.NewTicker(h.config.Thumbnail.Interval)
+	defer ticker.Stop()
+
+	for {
+		if len(h.config.Thumbnail.Command) > 0 {
+			if h.checkStreamIsLive() {
+				data, err := h.captureThumbnail()
+				if err != nil {
+					h.logger.Warnf("Failed to capture thumbnail: %v", err)
+				} else {
+					h.thumbnail.Store(&thumbnail{data: data, etag: contentETag(data), generatedAt: time.Now()})
+				}
+			} else {
+				h.thumbnail.Store(nil)
+			}
+		}
+		h.refreshPlaceholder()
+		<-ticker.C
+	}
+}
+
+// refreshPlaceholder looks up the current or next DJ and renders their card
+// if it changed
+func (h *Handler) refreshPlaceholder() {
+	djName, avatarFile := h.placeholderDJ()
+	branding := h.config.Thumbnail.Branding
+
+	next := &placeholder{djName: djName, branding: branding, avatarFile: avatarFile}
+	if current := h.placeholder.Load(); current != nil && current.djName == djName && current.branding == branding {
+		next.thumbnail = current.thumbnail
+	} else {
+		data, err := placeholderImage(h.placeholderFont, djName, branding)
+		if err != nil {
+			h.logger.Errorf("Failed to render placeholder thumbnail: %v", err)
+			return
+		}
+		next.thumbnail = thumbnail{data: data, etag: contentETag(data), generatedAt: time.Now()}
+	}
+	h.placeholder.Store(next)
+}
+
+// captureThumbnail runs the configured command and returns the JPEG it
+// wrote to stdout
+func (h *Handler) captureThumbnail() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Thumbnail.Timeout)
+	defer cancel()
+
+	args := h.config.Thumbnail.Command
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	stdout := &cappedBuffer{max: maxThumbnailBytes}
+	stderr := restream.NewTailBuffer()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if output := restream.LastLine(stderr.String()); output != "" {
+			return nil, fmt.Errorf("%w: %s", err, output)
+		}
+		return nil, err
+	}
+
+	data := stdout.Bytes()
+	if contentType := http.DetectContentType(data); contentType != "image/jpeg" {
+		return nil, fmt.Errorf("command wrote %s instead of a JPEG", contentType)
+	}
+	return data, nil
+}
+
+// GetStreamThumbnail serves the latest snapshot of the live stream, or a
+// placeholder for the current or next DJ while offline
+func (h *Handler) GetStreamThumbnail(w http.ResponseWriter, r *http.Request) {
+	interval := h.config.Thumbnail.Interval
+	if snapshot := h.thumbnail.Load(); snapshot != nil && time.Since(snapshot.generatedAt) < thumbnailMaxAge*interval {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(interval.Seconds())))
+		w.Header().Set("ETag", snapshot.etag)
+		w.Header().Set("Content-Type", "image/jpeg")
+		http.ServeContent(w, r, "", snapshot.generatedAt, bytes.NewReader(snapshot.data))
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", placeholderMaxAge))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	offline := h.placeholder.Load()
+	if offline == nil {
+		// Only when rendering failed, which has been logged
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to render thumbnail")
+		return
+	}
+	if offline.avatarFile != "" {
+		if h.serveAvatarFile(w, r, offline.avatarFile) {
+			return
+		}
+	}
+
+	w.Header().Set("ETag", offline.etag)
+	w.Header().Set("Content-Type", "image/jpeg")
+	http.ServeContent(w, r, "", offline.generatedAt, bytes.NewReader(offline.data))
+}
+
+// serveAvatarFile serves an avatar from the media directory. It returns
+// false without writing a response when the file cannot be read.
+func (h *Handler) serveAvatarFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	file, err := os.Open(filepath.Join(h.config.Media.Dir, name))
+	if err != nil {
+		h.logger.Warnf("Failed to open avatar for thumbnail: %v", err)
+		return false
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		h.logger.Warnf("Failed to stat avatar for thumbnail: %v", err)
+		return false
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+	return true
+}
+
+// placeholderDJ returns the name of the current DJ, or the next one
+// between sets, and the first avatar in their lineup
+func (h *Handler) placeholderDJ() (djName, avatarFile string) {
+	currentNext, err := h.db.GetCurrentNextDJ()
+	if err != nil {
+		h.logger.Errorf("Failed to get current/next DJ: %v", err)
+		return "", ""
+	}
+
+	var id uuid.UUID
+	switch {
+	case currentNext.CurrentID != nil && currentNext.CurrentDJName != nil:
+		id, djName = *currentNext.CurrentID, *currentNext.CurrentDJName
+	case currentNext.NextID != nil && currentNext.NextDJName != nil:
+		id, djName = *currentNext.NextID, *currentNext.NextDJName
+	default:
+		return "", ""
+	}
+
+	performers, err := h.db.GetPerformers([]uuid.UUID{id})
+	if err != nil {
+		h.logger.Errorf("Failed to get performers: %v", err)
+		return djName, ""
+	}
+	var profileIDs []uuid.UUID
+	for _, performer := range performers[id] {
+		if performer.ProfileID != nil {
+			profileIDs = append(profileIDs, *performer.ProfileID)
+		}
+	}
+	profiles, err := h.db.GetDJProfiles(profileIDs)
+	if err != nil {
+		h.logger.Errorf("Failed to get DJ profiles: %v", err)
+		return djName, ""
+	}
+	for _, profileID := range profileIDs {
+		if profile, ok := profiles[profileID]; ok && profile.AvatarFile != nil {
+			return djName, *profile.AvatarFile
+		}
+	}
+	return djName, ""
+}
+
+// placeholderImage renders a card with the DJ name and the branding. Its
+// color is derived from the DJ name, gray when nobody is scheduled.
+func placeholderImage(fnt *sfnt.Font, djName, branding string) ([]byte, error) {
+	fill := color.RGBA{R: 0x30, G: 0x30, B: 0x30, A: 0xff}
+	if djName != "" {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(djName))
+		sum := hash.Sum32()
+		// Muted colors keep the white text readable
+		fill = color.RGBA{R: uint8(0x20 + sum%0x80), G: uint8(0x20 + (sum>>8)%0x80), B: uint8(0x20 + (sum>>16)%0x80), A: 0xff}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, placeholderWidth, placeholderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: fill}, image.Point{}, draw.Src)
+
+	if djName != "" {
+		if err := drawCentered(img, fnt, djName, placeholderNameSizes, placeholderHeight/2, color.White); err != nil {
+			return nil, err
+		}
+	}
+	if branding != "" {
+		brandingColor := color.RGBA{R: 0xd0, G: 0xd0, B: 0xd0, A: 0xff}
+		if err := drawCentered(img, fnt, branding, placeholderBrandingSizes, placeholderHeight-placeholderMargin, brandingColor); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawCentered draws text horizontally centered with its middle at y, in
+// the largest of sizes that fits the card. Text too wide even at the
+// smallest size is shortened with an ellipsis.
+func drawCentered(img *image.RGBA, fnt *sfnt.Font, text string, sizes []float64, y int, c color.Color) error {
+	maxWidth := fixed.I(placeholderWidth - 2*placeholderMargin)
+
+	var face font.Face
+	for _, size := range sizes {
+		var err error
+		face, err = opentype.NewFace(fnt, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return err
+		}
+		if font.MeasureString(face, text) <= maxWidth {
+			break
+		}
+	}
+	defer face.Close()
+
+	for runes := []rune(text); font.MeasureString(face, text) > maxWidth && len(runes) > 1; {
+		runes = runes[:len(runes)-1]
+		text = strings.TrimSpace(string(runes)) + "…"
+	}
+
+	metrics := face.Metrics()
+	drawer := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face}
+	drawer.Dot = fixed.Point26_6{
+		X: (fixed.I(placeholderWidth) - drawer.MeasureString(text)) / 2,
+		Y: fixed.I(y) + (metrics.Ascent-metrics.Descent)/2,
+	}
+	drawer.DrawString(text)
+	return nil
+}
+
+// loadPlaceholderFont parses THUMBNAIL_FONT_FILE, falling back to the
+// embedded Go font. Of a font collection the first font is used, which is
+// the Japanese one in Noto Sans CJK.
+func loadPlaceholderFont(path string, logger *logrus.Logger) *sfnt.Font {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var fnt *sfnt.Font
+			if fnt, err = opentype.Parse(data); err == nil {
+				return fnt
+			}
+			var collection *opentype.Collection
+			if collection, err = opentype.ParseCollection(data); err == nil {
+				if fnt, err = collection.Font(0); err == nil {
+					return fnt
+				}
+			}
+		}
+		logger.Warnf("Failed to load THUMBNAIL_FONT_FILE %s, using the built-in font: %v", path, err)
+	}
+
+	fnt, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		logger.Fatalf("Failed to parse the built-in font: %v", err)
+	}
+	return fnt
+}
+
+func contentETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// cappedBuffer fails writes beyond max bytes, which stops runaway commands
+type cappedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, errThumbnailTooLarge
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// maxTrackFieldLength limits the artist and title of a track
+const maxTrackFieldLength = 200
+
+// trackClockSkew tolerates submitted timestamps slightly ahead of the server clock
+const trackClockSkew = time.Minute
+
+// GetTracklist returns the tracks played during a reservation
+func (h *Handler) GetTracklist(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid reservation ID")
+		return
+	}
+
+	if _, err := h.db.GetReservation(id); err != nil {
+		if err.Error() == "reservation not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Reservation not found")
+			return
+		}
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get tracklist")
+		return
+	}
+
+	tracks, err := h.db.GetTracklist(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get tracklist: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get tracklist")
+		return
+	}
+
+	apiTracks := make([]Track, len(tracks))
+	for i, track := range tracks {
+		apiTracks[i] = toAPITrack(track)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiTracks)
+}
+
+// SubmitTrack adds a track to a reservation's tracklist. A track submitted
+// while the set is on air is announced to viewers as now playing.
+func (h *Handler) SubmitTrack(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authenticateReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var req SubmitTrackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	artist := strings.TrimSpace(req.Artist)
+	title := strings.TrimSpace(req.Title)
+	if artist == "" || title == "" {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TRACK", "Artist and title are required")
+		return
+	}
+	if utf8.RuneCountInString(artist) > maxTrackFieldLength || utf8.RuneCountInString(title) > maxTrackFieldLength {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TRACK", "Artist and title must be at most 200 characters")
+		return
+	}
+
+	reservation, err := h.db.GetReservation(id)
+	if err != nil {
+		h.logger.Errorf("Failed to get reservation: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to submit track")
+		return
+	}
+
+	now := time.Now()
+	playedAt := now
+	if req.PlayedAt != nil {
+		playedAt = *req.PlayedAt
+	}
+	if playedAt.After(now.Add(trackClockSkew)) {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TRACK", "Track cannot be played in the future")
+		return
+	}
+	if playedAt.Before(reservation.StartTime) || !playedAt.Before(reservation.EndTime) {
+		h.sendError(w, http.StatusBadRequest, "INVALID_TRACK", "Track must be played during the reservation")
+		return
+	}
+
+	track, err := h.db.AddTrack(id, artist, title, playedAt)
+	if err != nil {
+		h.logger.Errorf("Failed to add track: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to submit track")
+		return
+	}
+
+	// Only the newest track of the set on air is announced, so late
+	// additions to the tracklist do not interrupt the current one
+	if !now.Before(reservation.StartTime) && now.Before(reservation.EndTime) {
+		current, err := h.db.GetCurrentTrack(id, now.Add(trackClockSkew))
+		if err != nil {
+			h.logger.Errorf("Failed to get current track: %v", err)
+		} else if current != nil && current.ID == track.ID {
+			h.wsManager.Broadcast(websocket.TypeNowPlaying, websocket.NowPlayingPayload{
+				TrackID:       track.ID,
+				ReservationID: id,
+				DJName:        reservation.DJName,
+				Artist:        track.Artist,
+				Title:         track.Title,
+				PlayedAt:      track.PlayedAt,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(toAPITrack(*track))
+}
+
+func toAPITrack(track db.Track) Track {
+	return Track{
+		Id:            openapi_types.UUID(track.ID),
+		ReservationId: openapi_types.UUID(track.ReservationID),
+		Artist:        track.Artist,
+		Title:         track.Title,
+		PlayedAt:      track.PlayedAt,
+	}
+}
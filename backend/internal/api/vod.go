@@ -0,0 +1,302 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// GetVODs lists finished sets. Hidden sets are only listed for admins.
+func (h *Handler) GetVODs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.VODFilter{DJName: strings.TrimSpace(query.Get("dj"))}
+
+	if value := query.Get("profileId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid profile ID")
+			return
+		}
+		filter.ProfileID = &id
+	}
+
+	// Dates are days in the event timezone
+	if value := query.Get("date"); value != "" {
+		loc, err := time.LoadLocation(h.config.EventTimezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		day, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "date must be YYYY-MM-DD")
+			return
+		}
+		next := day.AddDate(0, 0, 1)
+		filter.From, filter.To = &day, &next
+	}
+
+	_, filter.IncludeHidden = h.authenticateAdmin(r)
+
+	vods, err := h.db.GetVODs(filter)
+	if err != nil {
+		h.logger.Errorf("Failed to get vods: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VODs")
+		return
+	}
+
+	ids := make([]uuid.UUID, len(vods))
+	for i, vod := range vods {
+		ids[i] = vod.ReservationID
+	}
+	lineups, err := h.getLineups(ids)
+	if err != nil {
+		h.logger.Errorf("Failed to get lineups: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VODs")
+		return
+	}
+
+	apiVODs := make([]Vod, len(vods))
+	for i, vod := range vods {
+		apiVODs[i] = h.toAPIVOD(vod, lineups[vod.ReservationID])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiVODs)
+}
+
+// GetVOD returns one set with a fresh playback URL. Hidden sets need the
+// reservation passcode or an admin token.
+func (h *Handler) GetVOD(w http.ResponseWriter, r *http.Request) {
+	vod, ok := h.findVOD(w, r)
+	if !ok {
+		return
+	}
+	if !vod.Public && !h.authorizeVOD(w, r, vod) {
+		return
+	}
+
+	lineups, err := h.getLineups([]uuid.UUID{vod.ReservationID})
+	if err != nil {
+		h.logger.Errorf("Failed to get lineups: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VOD")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.toAPIVOD(*vod, lineups[vod.ReservationID]))
+}
+
+// UpdateVODVisibility publishes or hides a set. It needs the reservation
+// passcode or an admin token.
+func (h *Handler) UpdateVODVisibility(w http.ResponseWriter, r *http.Request) {
+	vod, ok := h.findVOD(w, r)
+	if !ok {
+		return
+	}
+	if !h.authorizeVOD(w, r, vod) {
+		return
+	}
+
+	var req UpdateVodVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if err := h.db.SetVODPublic(vod.ID, req.Public); err != nil {
+		h.logger.Errorf("Failed to update vod: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to update VOD")
+		return
+	}
+	vod.Public = req.Public
+
+	lineups, err := h.getLineups([]uuid.UUID{vod.ReservationID})
+	if err != nil {
+		h.logger.Errorf("Failed to get lineups: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VOD")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.toAPIVOD(*vod, lineups[vod.ReservationID]))
+}
+
+// PlayVOD streams a set as fragmented MP4 from the MediaMTX playback server.
+// The URL must carry a valid signature, so only URLs handed out by the VOD
+// endpoints work.
+func (h *Handler) PlayVOD(w http.ResponseWriter, r *http.Request) {
+	if h.config.MediaMTX.PlaybackURL == "" {
+		h.sendError(w, http.StatusServiceUnavailable, "VOD_UNAVAILABLE", "VOD playback is not configured")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "vodId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid VOD ID")
+		return
+	}
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires ||
+		!hmac.Equal([]byte(h.signVOD(id, expires)), []byte(r.URL.Query().Get("signature"))) {
+		h.sendError(w, http.StatusForbidden, "INVALID_SIGNATURE", "Playback URL is invalid or expired")
+		return
+	}
+
+	vod, err := h.db.GetVOD(id)
+	if err != nil {
+		if err.Error() == "vod not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "VOD not found")
+			return
+		}
+		h.logger.Errorf("Failed to get vod: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VOD")
+		return
+	}
+
+	source := mediamtx.PlaybackURL(h.config.MediaMTX.PlaybackURL, vod.PathName, vod.StartedAt, vod.EndedAt.Sub(vod.StartedAt))
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, source, nil)
+	if err != nil {
+		h.logger.Errorf("Failed to build playback request: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to play VOD")
+		return
+	}
+
+	// Seeking players request byte ranges, which the playback server answers
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+		if ifRange := r.Header.Get("If-Range"); ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		h.logger.Errorf("Playback request failed: %v", err)
+		h.sendError(w, http.StatusBadGateway, "VOD_UNAVAILABLE", "Recording is not available")
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		if contentRange := resp.Header.Get("Content-Range"); contentRange != "" {
+			w.Header().Set("Content-Range", contentRange)
+		}
+		h.sendError(w, http.StatusRequestedRangeNotSatisfiable, "INVALID_RANGE", "Requested range is not available")
+		return
+	default:
+		h.logger.Warnf("Playback server returned status %d for vod %s", resp.StatusCode, id)
+		h.sendError(w, http.StatusBadGateway, "VOD_UNAVAILABLE", "Recording is not available")
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp4")
+	for _, header := range []string{"Content-Length", "Content-Range", "Accept-Ranges", "ETag", "Last-Modified"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.mp4"`, id))
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		h.logger.Debugf("VOD playback of %s ended early: %v", id, err)
+	}
+}
+
+// findVOD loads the VOD named by the vodId URL parameter. An error response
+// has already been written when it returns false.
+func (h *Handler) findVOD(w http.ResponseWriter, r *http.Request) (*db.VOD, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "vodId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid VOD ID")
+		return nil, false
+	}
+
+	vod, err := h.db.GetVOD(id)
+	if err != nil {
+		if err.Error() == "vod not found" {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "VOD not found")
+			return nil, false
+		}
+		h.logger.Errorf("Failed to get vod: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to get VOD")
+		return nil, false
+	}
+	return vod, true
+}
+
+// authorizeVOD accepts an admin token or the passcode of the VOD's
+// reservation. An error response has already been written when it returns
+// false.
+func (h *Handler) authorizeVOD(w http.ResponseWriter, r *http.Request, vod *db.VOD) bool {
+	if _, ok := h.authenticateAdmin(r); ok {
+		return true
+	}
+
+	passcode := r.Header.Get("X-Reservation-Passcode")
+	if passcode == "" {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Reservation passcode or admin token required")
+		return false
+	}
+	if err := h.db.VerifyReservationPasscode(vod.ReservationID, passcode); err != nil {
+		if err.Error() == "invalid passcode" || err.Error() == "reservation not found" {
+			h.sendError(w, http.StatusUnauthorized, "INVALID_PASSCODE", "Invalid passcode")
+			return false
+		}
+		h.logger.Errorf("Failed to verify passcode: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to verify passcode")
+		return false
+	}
+	return true
+}
+
+// signVOD signs a playback URL for a VOD until expires (Unix seconds)
+func (h *Handler) signVOD(id uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, h.urlSecret)
+	fmt.Fprintf(mac, "vod:%s:%d", id, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Handler) toAPIVOD(vod db.VOD, performers []Performer) Vod {
+	apiVOD := Vod{
+		Id:              openapi_types.UUID(vod.ID),
+		ReservationId:   openapi_types.UUID(vod.ReservationID),
+		DjName:          vod.DJName,
+		Performers:      performers,
+		StartedAt:       vod.StartedAt,
+		EndedAt:         vod.EndedAt,
+		DurationSeconds: vod.EndedAt.Sub(vod.StartedAt).Seconds(),
+		Public:          vod.Public,
+	}
+	if apiVOD.Performers == nil {
+		apiVOD.Performers = []Performer{}
+	}
+
+	if h.config.MediaMTX.PlaybackURL != "" {
+		expiresAt := time.Now().Add(h.config.Media.VODURLTTL).Truncate(time.Second)
+		query := url.Values{}
+		query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+		query.Set("signature", h.signVOD(vod.ID, expiresAt.Unix()))
+		playbackURL := fmt.Sprintf("%s/api/v1/vod/%s/playback?%s", h.config.PublicURL, vod.ID, query.Encode())
+		apiVOD.PlaybackUrl = &playbackURL
+		apiVOD.PlaybackUrlExpiresAt = &expiresAt
+	}
+	return apiVOD
+}
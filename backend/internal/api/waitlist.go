@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// waitlistWebhookTimeout bounds each webhook delivery
+const waitlistWebhookTimeout = 5 * time.Second
+
+// errWebhookAddress rejects webhook connections to internal addresses
+var errWebhookAddress = errors.New("webhook address is not public")
+
+// nonPublicPrefixes are ranges that netip does not classify but that are not
+// reachable on the internet either
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// webhookClient delivers webhooks registered by anonymous callers. It only
+// connects to public addresses, checked after DNS resolution so that
+// rebinding cannot reach the internal networks, and does not follow
+// redirects or use a proxy.
+var webhookClient = &http.Client{
+	Timeout: waitlistWebhookTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: waitlistWebhookTimeout,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(address)
+				if err != nil || !publicAddr(addrPort.Addr()) {
+					return fmt.Errorf("%w: %s", errWebhookAddress, address)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: waitlistWebhookTimeout,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func (h *Handler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var req JoinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, openapi_types.ErrValidationEmail) {
+			h.sendError(w, http.StatusBadRequest, "INVALID_EMAIL", "Contact email is not a valid email address")
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if !h.validateDJName(w, req.DjName) {
+		return
+	}
+
+	var contactEmail *string
+	if req.ContactEmail != nil {
+		email := string(*req.ContactEmail)
+		if len(email) > 254 {
+			h.sendError(w, http.StatusBadRequest, "INVALID_EMAIL", "Contact email must be at most 254 characters")
+			return
+		}
+		contactEmail = &email
+	}
+
+	var webhookURL *string
+	if req.WebhookUrl != nil && *req.WebhookUrl != "" {
+		if !h.config.Booking.WaitlistWebhooks {
+			h.sendError(w, http.StatusBadRequest, "INVALID_WEBHOOK_URL", "Webhooks are not enabled")
+			return
+		}
+		if !validHTTPURL(*req.WebhookUrl, 2048) {
+			h.sendError(w, http.StatusBadRequest, "INVALID_WEBHOOK_URL", "Webhook URL must be an absolute http(s) URL of at most 2048 characters")
+			return
+		}
+		if !publicWebhookHost(*req.WebhookUrl) {
+			h.sendError(w, http.StatusBadRequest, "INVALID_WEBHOOK_URL", "Webhook URL must point to a public host")
+			return
+		}
+		webhookURL = req.WebhookUrl
+	}
+
+	if !h.validateSlotRange(w, req.StartTime, req.EndTime) {
+		return
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		h.logger.Errorf("Failed to generate waitlist token: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to join waitlist")
+		return
+	}
+
+	entry, position, err := h.db.CreateWaitlistEntry(req.DjName, req.StartTime, req.EndTime, contactEmail, webhookURL, tokenHash)
+	if err != nil {
+		h.logger.Errorf("Failed to create waitlist entry: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to join waitlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(WaitlistEntry{
+		Id:        openapi_types.UUID(entry.ID),
+		Token:     token,
+		DjName:    entry.DJName,
+		StartTime: entry.StartTime,
+		EndTime:   entry.EndTime,
+		Position:  position,
+		CreatedAt: entry.CreatedAt,
+	})
+}
+
+func (h *Handler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "entryId"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Invalid waitlist entry ID")
+		return
+	}
+
+	token := r.Header.Get("X-Waitlist-Token")
+	if token == "" {
+		h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Waitlist token is required")
+		return
+	}
+
+	hold, err := h.db.CancelWaitlistEntry(id, hashSecretToken(token))
+	if err != nil {
+		switch err.Error() {
+		case "invalid token":
+			h.sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid waitlist token")
+		case "waitlist entry not found":
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Waitlist entry not found")
+		default:
+			h.logger.Errorf("Failed to cancel waitlist entry: %v", err)
+			h.sendError(w, http.StatusInternalServerError, "DB_ERROR", "Failed to leave waitlist")
+		}
+		return
+	}
+
+	// A withdrawn offer goes to the next DJ in line
+	if hold != nil {
+		h.announceHoldChange(websocket.LineupReleased, *hold)
+		h.offerWaitlist(hold.StartTime, hold.EndTime)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// offerWaitlist gives freed time to the first waitlisted DJ who fits and
+// notifies them
+func (h *Handler) offerWaitlist(startTime, endTime time.Time) {
+	entry, err := h.db.OfferWaitlistSlot(startTime, endTime, time.Now().Add(h.config.Booking.WaitlistClaimWindow))
+	if err != nil {
+		h.logger.Errorf("Failed to offer freed slot to the waitlist: %v", err)
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	h.logger.Infof("Offered %s - %s to waitlist entry %s", entry.StartTime.Format(time.RFC3339), entry.EndTime.Format(time.RFC3339), entry.ID)
+
+	h.announceHoldChange(websocket.LineupHeld, db.SlotHold{StartTime: entry.StartTime, EndTime: entry.EndTime})
+	offer := websocket.WaitlistOfferPayload{
+		EntryID:   entry.ID,
+		DJName:    entry.DJName,
+		StartTime: entry.StartTime,
+		EndTime:   entry.EndTime,
+		ExpiresAt: *entry.OfferExpiresAt,
+	}
+	h.wsManager.Broadcast(websocket.TypeWaitlistOffer, offer)
+
+	// Mail and webhooks may be slow; the caller is usually an HTTP handler
+	go h.sendWaitlistNotifications(*entry, offer)
+}
+
+func (h *Handler) sendWaitlistNotifications(entry db.WaitlistEntry, offer websocket.WaitlistOfferPayload) {
+	if entry.ContactEmail != nil && h.mailer.Enabled() {
+		if err := h.mailer.Send(*entry.ContactEmail, "キャンセル待ちの時間枠が空きました", h.buildWaitlistMail(offer)); err != nil {
+			h.logger.Errorf("Failed to send waitlist mail for entry %s: %v", entry.ID, err)
+		}
+	}
+
+	if entry.WebhookURL != nil && h.config.Booking.WaitlistWebhooks {
+		if err := postWaitlistWebhook(*entry.WebhookURL, offer); err != nil {
+			h.logger.Warnf("Failed to deliver waitlist webhook for entry %s: %v", entry.ID, err)
+		}
+	}
+}
+
+func (h *Handler) buildWaitlistMail(offer websocket.WaitlistOfferPayload) string {
+	loc, err := time.LoadLocation(h.config.EventTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return fmt.Sprintf(`%s さん
+
+キャンセル待ちに登録した時間枠が空きました。
+
+%s 〜 %s
+
+この時間枠は %s まであなた専用に確保されています。
+キャンセル待ち登録時に受け取ったトークンを使って予約ページから予約してください。
+
+%s
+`, offer.DJName,
+		offer.StartTime.In(loc).Format("2006-01-02 15:04"), offer.EndTime.In(loc).Format("15:04"),
+		offer.ExpiresAt.In(loc).Format("2006-01-02 15:04"), h.config.PublicURL)
+}
+
+// postWaitlistWebhook delivers an offer as {"event": "waitlist_offer", ...}
+func postWaitlistWebhook(target string, offer websocket.WaitlistOfferPayload) error {
+	body, err := json.Marshal(struct {
+		Event string `json:"event"`
+		websocket.WaitlistOfferPayload
+	}{Event: websocket.TypeWaitlistOffer, WaitlistOfferPayload: offer})
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// publicWebhookHost rejects webhook URLs whose host is obviously internal.
+// Names are checked again on every connection by webhookClient.
+func publicWebhookHost(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return publicAddr(addr)
+	}
+	// Single-label names resolve to other containers, e.g. "backend"
+	return strings.Contains(host, ".")
+}
+
+// publicAddr reports whether an address is routable on the internet
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validHTTPURL reports whether raw is an absolute http(s) URL of at most
+// maxLength bytes
+func validHTTPURL(raw string, maxLength int) bool {
+	if len(raw) > maxLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
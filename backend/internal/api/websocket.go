@@ -1,22 +1,161 @@
 package api
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/dj-event/stream-system/internal/websocket"
 )
 
 func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := h.upgrader.Upgrade(w, r, nil)
+	ip := clientIP(r)
+	if !h.wsManager.AcquireIP(ip) {
+		h.logger.Warnf("Rejected WebSocket connection from %s: too many connections", ip)
+		h.sendError(w, http.StatusTooManyRequests, "TOO_MANY_CONNECTIONS", "Too many connections from this address")
+		return
+	}
+
+	viewerID, token, isNew := h.resolveViewer(r)
+
+	var responseHeader http.Header
+	if isNew {
+		responseHeader = http.Header{"Set-Cookie": {h.viewerCookie(token).String()}}
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
+		h.wsManager.ReleaseIP(ip)
 		h.logger.Errorf("Failed to upgrade connection: %v", err)
 		return
 	}
 
-	client := websocket.NewClient(conn, h.wsManager)
+	client := websocket.NewClient(conn, h.wsManager, ip, viewerID)
+
+	// Hand the token to clients that cannot rely on cookies so they can
+	// pass it back as ?viewerToken= when reconnecting
+	client.SendMessage(websocket.TypeViewerToken, websocket.ViewerTokenPayload{Token: token})
+
 	h.wsManager.Register(client)
 
 	// Start goroutines for reading and writing
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+const (
+	viewerCookieName   = "dsr_viewer"
+	viewerCookieMaxAge = 365 * 24 * time.Hour
+)
+
+// viewerCookie remembers a newly issued viewer token in the browser
+func (h *Handler) viewerCookie(token string) *http.Cookie {
+	return &http.Cookie{
+		Name:     viewerCookieName,
+		Value:    token,
+		Path:     "/api/v1",
+		MaxAge:   int(viewerCookieMaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.config.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// resolveViewer identifies the viewer from the viewerToken query parameter
+// or cookie. A new identity is issued if neither carries a valid token.
+func (h *Handler) resolveViewer(r *http.Request) (string, string, bool) {
+	token := r.URL.Query().Get("viewerToken")
+	if token == "" {
+		if cookie, err := r.Cookie(viewerCookieName); err == nil {
+			token = cookie.Value
+		}
+	}
+
+	if token != "" {
+		if id, ok := h.tokens.Verify(token); ok {
+			return id, token, false
+		}
+	}
+
+	id, token := h.tokens.Issue()
+	return id, token, true
+}
+
+// pollExternalViewers periodically reads the reader list of the stream path
+// from the MediaMTX API and feeds it into the viewer count
+func (h *Handler) pollExternalViewers() {
+	ticker := time.NewTicker(h.config.MediaMTX.PollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		path, err := h.mediamtx.GetPath(h.config.MediaMTX.PathName)
+		if err != nil {
+			h.logger.Debugf("Failed to get MediaMTX path: %v", err)
+			h.wsManager.SetExternalViewers(0)
+			continue
+		}
+		h.wsManager.SetExternalViewers(path.CountExternalReaders())
+	}
+}
+
+// HandleMetrics exposes WebSocket counters in the Prometheus text format.
+// It is served outside /api so that nginx does not publish it.
+func (h *Handler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	h.wsManager.WriteMetrics(w)
+}
+
+// checkOrigin accepts requests whose Origin matches WS_ALLOWED_ORIGINS.
+// Requests without an Origin header come from non-browser clients, which
+// cannot be embedded by third-party pages, so they are allowed.
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	normalized, err := normalizeOrigin(origin)
+	if err == nil {
+		for _, allowed := range h.config.WebSocket.AllowedOrigins {
+			if allowed == "*" {
+				return true
+			}
+			if a, err := normalizeOrigin(allowed); err == nil && a == normalized {
+				return true
+			}
+		}
+	}
+
+	h.wsManager.Metrics().RecordRejection(websocket.RejectOrigin)
+	h.logger.Warnf("Rejected WebSocket connection from %s: origin %q not allowed", clientIP(r), origin)
+	return false
+}
+
+// normalizeOrigin reduces an origin or URL to lowercase scheme://host[:port]
+func normalizeOrigin(origin string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid origin: %s", origin)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// clientIP returns the address of the viewer. nginx sets X-Real-IP on
+// proxied requests; RemoteAddr is used when the backend is accessed directly.
+func clientIP(r *http.Request) string {
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
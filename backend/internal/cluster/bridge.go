@@ -0,0 +1,120 @@
+// Package cluster connects backend replicas through Postgres LISTEN/NOTIFY so
+// that viewer counts and WebSocket broadcasts span all of them.
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/db"
+	"github.com/dj-event/stream-system/internal/websocket"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// Channel is the notification channel shared by all replicas
+const Channel = "stream_system_events"
+
+const (
+	// maxPayload keeps notifications below Postgres' 8000 byte limit
+	maxPayload = 7900
+	// outboxSize bounds events waiting to be published
+	outboxSize = 1024
+)
+
+type Config struct {
+	// DSN is used for the dedicated LISTEN connection
+	DSN string
+	// ReplicaID identifies this replica's events
+	ReplicaID string
+}
+
+// Bridge implements websocket.Cluster on top of Postgres notifications
+type Bridge struct {
+	db        *db.DB
+	listener  *pq.Listener
+	replicaID string
+	outbox    chan websocket.ClusterEvent
+	logger    *logrus.Logger
+}
+
+func New(cfg Config, database *db.DB, logger *logrus.Logger) (*Bridge, error) {
+	listener := pq.NewListener(cfg.DSN, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warnf("Cluster listener: %v", err)
+		}
+	})
+	if err := listener.Listen(Channel); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", Channel, err)
+	}
+
+	logger.Infof("Cluster bridge started as replica %s", cfg.ReplicaID)
+
+	return &Bridge{
+		db:        database,
+		listener:  listener,
+		replicaID: cfg.ReplicaID,
+		outbox:    make(chan websocket.ClusterEvent, outboxSize),
+		logger:    logger,
+	}, nil
+}
+
+// Publish queues an event for the other replicas. Events are dropped when
+// the database cannot keep up rather than stalling the caller.
+func (b *Bridge) Publish(event websocket.ClusterEvent) {
+	event.Replica = b.replicaID
+	select {
+	case b.outbox <- event:
+	default:
+		b.logger.Warnf("Cluster outbox full, dropping %s event", event.Kind)
+	}
+}
+
+// Run publishes queued events and hands events from other replicas to
+// handle until the listener is closed
+func (b *Bridge) Run(handle func(websocket.ClusterEvent)) {
+	go b.publishLoop()
+
+	for notification := range b.listener.Notify {
+		// A nil notification signals a reconnect; missed events are not
+		// replayed, counts recover with the next heartbeat
+		if notification == nil {
+			b.logger.Info("Cluster listener reconnected")
+			continue
+		}
+
+		var event websocket.ClusterEvent
+		if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+			b.logger.Warnf("Ignoring malformed cluster event: %v", err)
+			continue
+		}
+		if event.Replica == b.replicaID {
+			continue
+		}
+		handle(event)
+	}
+}
+
+func (b *Bridge) publishLoop() {
+	for event := range b.outbox {
+		data, err := json.Marshal(event)
+		if err != nil {
+			b.logger.Errorf("Failed to encode cluster event: %v", err)
+			continue
+		}
+		if len(data) > maxPayload {
+			b.logger.Warnf("Cluster %s event is %d bytes, too large to publish", event.Kind, len(data))
+			continue
+		}
+
+		if _, err := b.db.Exec(`SELECT pg_notify($1, $2)`, Channel, string(data)); err != nil {
+			b.logger.Errorf("Failed to publish cluster event: %v", err)
+		}
+	}
+}
+
+func (b *Bridge) Close() error {
+	return b.listener.Close()
+}
@@ -3,14 +3,32 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	Server         ServerConfig
 	Database       DatabaseConfig
+	Passcode       PasscodeConfig
+	Booking        BookingConfig
+	SMTP           SMTPConfig
+	WebSocket      WebSocketConfig
+	Chat           ChatConfig
+	Reactions      ReactionConfig
+	Moderation     ModerationConfig
+	Admin          AdminConfig
+	MediaMTX       MediaMTXConfig
+	Ingest         IngestConfig
+	Thumbnail      ThumbnailConfig
+	Restream       RestreamConfig
+	Media          MediaConfig
+	Cluster        ClusterConfig
 	LogLevel       string
+	PublicURL      string
 	EventStartTime *time.Time
 	EventEndTime   *time.Time
 	EventTimezone  string
@@ -30,6 +48,171 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// Passcode character sets accepted by PASSCODE_CHARSET
+const (
+	PasscodeCharsetNumeric      = "numeric"
+	PasscodeCharsetAlphanumeric = "alphanumeric"
+	PasscodeCharsetAny          = "any"
+)
+
+// bcrypt ignores everything past 72 bytes, so longer passcodes are rejected
+const maxPasscodeBytes = 72
+
+type PasscodeConfig struct {
+	MinLength   int
+	MaxLength   int
+	Charset     string
+	RecoveryTTL time.Duration
+}
+
+type BookingConfig struct {
+	// HoldDuration is how long POST /slot-holds keeps a range for the caller
+	HoldDuration time.Duration
+	// MaxHoldsPerIP caps the active holds of one client address, so that
+	// a caller cannot hold a new range while still holding one (0 = no cap)
+	MaxHoldsPerIP int
+	// MaxHeldPerIP caps the total time held by one client address
+	MaxHeldPerIP time.Duration
+	// WaitlistClaimWindow is how long a waitlisted DJ has to book a freed range
+	WaitlistClaimWindow time.Duration
+	// WaitlistWebhooks allows waitlist entries to register a webhook URL
+	WaitlistWebhooks bool
+	// SlotEndGrace is how long a DJ may keep publishing after their slot ends
+	SlotEndGrace time.Duration
+	// KickOnSlotEnd disconnects the publisher through the MediaMTX API once
+	// the grace period is over
+	KickOnSlotEnd bool
+	// TakeoverLead is how long before their slot the next DJ may take over
+	// the stream without being kicked when the previous slot ends
+	TakeoverLead time.Duration
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type WebSocketConfig struct {
+	// AllowedOrigins lists the Origin values accepted on /ws/viewer.
+	// A single "*" entry disables the check.
+	AllowedOrigins      []string
+	MaxConnectionsPerIP int
+	MaxMessageSize      int64
+	// ViewerCountDebounce is the minimum interval between viewer count broadcasts
+	ViewerCountDebounce time.Duration
+	// ViewerTokenSecret signs anonymous viewer tokens. When empty a random
+	// secret is generated at startup and tokens reset on every restart.
+	ViewerTokenSecret string
+}
+
+type ChatConfig struct {
+	MaxLength    int
+	HistorySize  int
+	RateBurst    int
+	RateInterval time.Duration
+}
+
+type ReactionConfig struct {
+	Allowed      []string
+	RateBurst    int
+	RateInterval time.Duration
+}
+
+type ModerationConfig struct {
+	// Moderators maps access tokens to moderator names
+	Moderators       map[string]string
+	BlockedWords     []string
+	SubscriberMinAge time.Duration
+}
+
+type AdminConfig struct {
+	// Admins maps access tokens to admin names
+	Admins map[string]string
+}
+
+type MediaMTXConfig struct {
+	// APIURL is the MediaMTX control API base URL (empty = not used)
+	APIURL       string
+	PathName     string
+	PollInterval time.Duration
+	// MediaDir is where the media volume is mounted inside the MediaMTX
+	// container, used to map hook file paths to Media.Dir
+	MediaDir string
+	// PlaybackURL is the MediaMTX playback server base URL (empty = no VOD playback)
+	PlaybackURL string
+	// FallbackFile is looped on FallbackPath while no DJ publishes, relative
+	// to the media volume (empty = no fallback stream)
+	FallbackFile string
+	FallbackPath string
+	// FallbackUser and FallbackPass let the fallback ffmpeg publish on
+	// FallbackPath; mediamtx.yml grants them from localhost only
+	FallbackUser string
+	FallbackPass string
+}
+
+// fallbackFilePattern keeps the fallback file usable in the MediaMTX
+// runOnInit command line
+var fallbackFilePattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// fallbackCredentialPattern keeps the credentials usable in the RTSP URL of
+// the runOnInit command line
+var fallbackCredentialPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// IngestConfig controls the ingest health monitor, which needs MediaMTX.APIURL
+type IngestConfig struct {
+	PollInterval time.Duration
+	// History is how much of the rolling health history is kept
+	History time.Duration
+	// BitrateDropPercent warns when the bitrate falls below this percentage
+	// of the previous minute's average
+	BitrateDropPercent int
+	// ReconnectLimit warns when the publisher connects this many times
+	// within ReconnectWindow
+	ReconnectLimit  int
+	ReconnectWindow time.Duration
+}
+
+type ThumbnailConfig struct {
+	// Command writes a JPEG snapshot of the live stream to stdout; it is
+	// split on whitespace and run without a shell (empty = placeholders only)
+	Command  []string
+	Interval time.Duration
+	Timeout  time.Duration
+	// FontFile is a TrueType/OpenType font (or collection) for the DJ name
+	// on placeholders; the embedded Go font has no Japanese glyphs
+	FontFile string
+	// Branding is printed below the DJ name on placeholders
+	Branding string
+}
+
+type RestreamConfig struct {
+	// FFmpeg forwards the stream to the restream targets
+	FFmpeg string
+	// SourceURL is the live stream as read by ffmpeg
+	SourceURL string
+}
+
+type MediaConfig struct {
+	// Dir is the media volume shared with MediaMTX (avatars, recordings, ...)
+	Dir            string
+	AvatarMaxBytes int64
+	// VODURLTTL is how long signed VOD playback URLs stay valid
+	VODURLTTL time.Duration
+	// VODURLSecret signs VOD playback URLs. It defaults to the viewer token
+	// secret.
+	VODURLSecret string
+}
+
+type ClusterConfig struct {
+	// Enabled connects replicas through Postgres LISTEN/NOTIFY
+	Enabled bool
+	// ReplicaID must be unique per replica; defaults to the hostname
+	ReplicaID string
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
@@ -44,7 +227,221 @@ func Load() (*Config, error) {
 			DBName:   getEnv("DB_NAME", "stream_system"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
-		LogLevel: getEnv("LOG_LEVEL", "info"),
+		Passcode: PasscodeConfig{
+			MinLength:   getEnvAsInt("PASSCODE_MIN_LENGTH", 4),
+			MaxLength:   getEnvAsInt("PASSCODE_MAX_LENGTH", 4),
+			Charset:     getEnv("PASSCODE_CHARSET", PasscodeCharsetNumeric),
+			RecoveryTTL: time.Duration(getEnvAsInt("PASSCODE_RECOVERY_TTL_MINUTES", 30)) * time.Minute,
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvAsInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", ""),
+		},
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		PublicURL: strings.TrimRight(getEnv("PRODUCTION_DOMAIN", "http://localhost"), "/"),
+	}
+
+	cfg.WebSocket = WebSocketConfig{
+		AllowedOrigins:      getEnvAsList("WS_ALLOWED_ORIGINS", []string{cfg.PublicURL}),
+		MaxConnectionsPerIP: getEnvAsInt("WS_MAX_CONNECTIONS_PER_IP", 10),
+		MaxMessageSize:      int64(getEnvAsInt("WS_MAX_MESSAGE_SIZE", 4096)),
+		ViewerCountDebounce: time.Duration(getEnvAsInt("WS_VIEWER_COUNT_DEBOUNCE_MS", 500)) * time.Millisecond,
+		ViewerTokenSecret:   getEnv("VIEWER_TOKEN_SECRET", ""),
+	}
+	if cfg.WebSocket.MaxMessageSize <= 0 {
+		return nil, fmt.Errorf("WS_MAX_MESSAGE_SIZE must be positive")
+	}
+	if cfg.WebSocket.ViewerCountDebounce <= 0 {
+		return nil, fmt.Errorf("WS_VIEWER_COUNT_DEBOUNCE_MS must be positive")
+	}
+
+	cfg.Booking = BookingConfig{
+		HoldDuration:        time.Duration(getEnvAsInt("SLOT_HOLD_SECONDS", 180)) * time.Second,
+		MaxHoldsPerIP:       getEnvAsInt("SLOT_HOLD_MAX_PER_IP", 1),
+		MaxHeldPerIP:        time.Duration(getEnvAsInt("SLOT_HOLD_MAX_MINUTES_PER_IP", 60)) * time.Minute,
+		WaitlistClaimWindow: time.Duration(getEnvAsInt("WAITLIST_CLAIM_MINUTES", 15)) * time.Minute,
+		WaitlistWebhooks:    getEnvAsBool("WAITLIST_WEBHOOKS_ENABLED", false),
+		SlotEndGrace:        time.Duration(getEnvAsInt("SLOT_END_GRACE_SECONDS", 30)) * time.Second,
+		KickOnSlotEnd:       getEnvAsBool("SLOT_END_KICK_ENABLED", true),
+		TakeoverLead:        time.Duration(getEnvAsInt("SLOT_TAKEOVER_LEAD_SECONDS", 300)) * time.Second,
+	}
+	if cfg.Booking.HoldDuration <= 0 {
+		return nil, fmt.Errorf("SLOT_HOLD_SECONDS must be positive")
+	}
+	if cfg.Booking.MaxHoldsPerIP < 0 {
+		return nil, fmt.Errorf("SLOT_HOLD_MAX_PER_IP must not be negative")
+	}
+	if cfg.Booking.MaxHeldPerIP < 0 {
+		return nil, fmt.Errorf("SLOT_HOLD_MAX_MINUTES_PER_IP must not be negative")
+	}
+	if cfg.Booking.WaitlistClaimWindow <= 0 {
+		return nil, fmt.Errorf("WAITLIST_CLAIM_MINUTES must be positive")
+	}
+	if cfg.Booking.SlotEndGrace < 0 {
+		return nil, fmt.Errorf("SLOT_END_GRACE_SECONDS must not be negative")
+	}
+	if cfg.Booking.TakeoverLead < 0 {
+		return nil, fmt.Errorf("SLOT_TAKEOVER_LEAD_SECONDS must not be negative")
+	}
+
+	cfg.Chat = ChatConfig{
+		MaxLength:    getEnvAsInt("CHAT_MAX_LENGTH", 300),
+		HistorySize:  getEnvAsInt("CHAT_HISTORY_SIZE", 50),
+		RateBurst:    getEnvAsInt("CHAT_RATE_BURST", 5),
+		RateInterval: time.Duration(getEnvAsInt("CHAT_RATE_INTERVAL_MS", 2000)) * time.Millisecond,
+	}
+	if cfg.Chat.MaxLength <= 0 {
+		return nil, fmt.Errorf("CHAT_MAX_LENGTH must be positive")
+	}
+	if cfg.Chat.RateBurst <= 0 {
+		return nil, fmt.Errorf("CHAT_RATE_BURST must be positive")
+	}
+
+	cfg.Reactions = ReactionConfig{
+		Allowed:      getEnvAsList("REACTIONS_ALLOWED", []string{"fire", "heart", "clap", "laugh", "wow"}),
+		RateBurst:    getEnvAsInt("REACTIONS_RATE_BURST", 20),
+		RateInterval: time.Duration(getEnvAsInt("REACTIONS_RATE_INTERVAL_MS", 200)) * time.Millisecond,
+	}
+	if cfg.Reactions.RateBurst <= 0 {
+		return nil, fmt.Errorf("REACTIONS_RATE_BURST must be positive")
+	}
+
+	cfg.Moderation = ModerationConfig{
+		BlockedWords:     getEnvAsList("CHAT_BLOCKED_WORDS", nil),
+		SubscriberMinAge: time.Duration(getEnvAsInt("CHAT_SUBSCRIBER_MIN_AGE_MINUTES", 10)) * time.Minute,
+	}
+	var err error
+	if cfg.Moderation.Moderators, err = getEnvAsTokens("MODERATOR_TOKENS"); err != nil {
+		return nil, err
+	}
+	if cfg.Admin.Admins, err = getEnvAsTokens("ADMIN_TOKENS"); err != nil {
+		return nil, err
+	}
+
+	cfg.MediaMTX = MediaMTXConfig{
+		APIURL:       getEnv("MEDIAMTX_API_URL", ""),
+		PathName:     getEnv("MEDIAMTX_PATH", "stream-endpoint"),
+		PollInterval: time.Duration(getEnvAsInt("MEDIAMTX_POLL_INTERVAL_SECONDS", 10)) * time.Second,
+		MediaDir:     getEnv("MEDIAMTX_MEDIA_DIR", "/media"),
+		PlaybackURL:  getEnv("MEDIAMTX_PLAYBACK_URL", ""),
+		FallbackFile: getEnv("FALLBACK_FILE", ""),
+		FallbackPath: getEnv("MEDIAMTX_FALLBACK_PATH", "fallback"),
+		FallbackUser: getEnv("MEDIAMTX_FALLBACK_USER", "fallback-publisher"),
+		FallbackPass: getEnv("MEDIAMTX_FALLBACK_PASS", "fallback-publisher-passwd"),
+	}
+	if cfg.MediaMTX.PollInterval <= 0 {
+		return nil, fmt.Errorf("MEDIAMTX_POLL_INTERVAL_SECONDS must be positive")
+	}
+	if file := cfg.MediaMTX.FallbackFile; file != "" {
+		if !fallbackFilePattern.MatchString(file) || path.IsAbs(file) || path.Clean(file) != file || file == ".." || strings.HasPrefix(file, "../") {
+			return nil, fmt.Errorf("FALLBACK_FILE must be a relative path of letters, digits and ._/- inside MEDIA_DIR")
+		}
+		if cfg.MediaMTX.FallbackPath == "" || cfg.MediaMTX.FallbackPath == cfg.MediaMTX.PathName {
+			return nil, fmt.Errorf("MEDIAMTX_FALLBACK_PATH must be set and differ from MEDIAMTX_PATH")
+		}
+		if !fallbackCredentialPattern.MatchString(cfg.MediaMTX.FallbackUser) || !fallbackCredentialPattern.MatchString(cfg.MediaMTX.FallbackPass) {
+			return nil, fmt.Errorf("MEDIAMTX_FALLBACK_USER and MEDIAMTX_FALLBACK_PASS must be letters, digits and ._-")
+		}
+	}
+
+	cfg.Ingest = IngestConfig{
+		PollInterval:       time.Duration(getEnvAsInt("INGEST_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+		History:            time.Duration(getEnvAsInt("INGEST_HISTORY_MINUTES", 10)) * time.Minute,
+		BitrateDropPercent: getEnvAsInt("INGEST_BITRATE_DROP_PERCENT", 50),
+		ReconnectLimit:     getEnvAsInt("INGEST_RECONNECT_LIMIT", 3),
+		ReconnectWindow:    time.Duration(getEnvAsInt("INGEST_RECONNECT_WINDOW_MINUTES", 5)) * time.Minute,
+	}
+	if cfg.Ingest.PollInterval <= 0 {
+		return nil, fmt.Errorf("INGEST_POLL_INTERVAL_SECONDS must be positive")
+	}
+	if cfg.Ingest.History <= 0 {
+		return nil, fmt.Errorf("INGEST_HISTORY_MINUTES must be positive")
+	}
+	if cfg.Ingest.BitrateDropPercent < 0 || cfg.Ingest.BitrateDropPercent >= 100 {
+		return nil, fmt.Errorf("INGEST_BITRATE_DROP_PERCENT must be between 0 and 99")
+	}
+	if cfg.Ingest.ReconnectLimit < 2 {
+		return nil, fmt.Errorf("INGEST_RECONNECT_LIMIT must be at least 2")
+	}
+	if cfg.Ingest.ReconnectWindow <= 0 {
+		return nil, fmt.Errorf("INGEST_RECONNECT_WINDOW_MINUTES must be positive")
+	}
+
+	cfg.Thumbnail = ThumbnailConfig{
+		Command:  strings.Fields(getEnv("THUMBNAIL_COMMAND", "")),
+		Interval: time.Duration(getEnvAsInt("THUMBNAIL_INTERVAL_SECONDS", 30)) * time.Second,
+		Timeout:  time.Duration(getEnvAsInt("THUMBNAIL_TIMEOUT_SECONDS", 15)) * time.Second,
+		FontFile: getEnv("THUMBNAIL_FONT_FILE", ""),
+		Branding: getEnv("THUMBNAIL_BRANDING", strings.TrimPrefix(strings.TrimPrefix(cfg.PublicURL, "https://"), "http://")),
+	}
+	if cfg.Thumbnail.Interval <= 0 {
+		return nil, fmt.Errorf("THUMBNAIL_INTERVAL_SECONDS must be positive")
+	}
+	if cfg.Thumbnail.Timeout <= 0 {
+		return nil, fmt.Errorf("THUMBNAIL_TIMEOUT_SECONDS must be positive")
+	}
+
+	cfg.Restream = RestreamConfig{
+		FFmpeg:    getEnv("RESTREAM_FFMPEG", "ffmpeg"),
+		SourceURL: getEnv("RESTREAM_SOURCE_URL", "rtsp://mediamtx:8554/stream-endpoint"),
+	}
+
+	cfg.Media = MediaConfig{
+		Dir:            getEnv("MEDIA_DIR", "./media"),
+		AvatarMaxBytes: int64(getEnvAsInt("AVATAR_MAX_KB", 2048)) * 1024,
+		VODURLTTL:      time.Duration(getEnvAsInt("VOD_URL_TTL_MINUTES", 360)) * time.Minute,
+		VODURLSecret:   getEnv("VOD_URL_SECRET", cfg.WebSocket.ViewerTokenSecret),
+	}
+	if cfg.Media.AvatarMaxBytes <= 0 {
+		return nil, fmt.Errorf("AVATAR_MAX_KB must be positive")
+	}
+	if cfg.Media.VODURLTTL <= 0 {
+		return nil, fmt.Errorf("VOD_URL_TTL_MINUTES must be positive")
+	}
+
+	cfg.Cluster = ClusterConfig{
+		Enabled:   getEnvAsBool("CLUSTER_ENABLED", false),
+		ReplicaID: getEnv("CLUSTER_REPLICA_ID", ""),
+	}
+	if cfg.Cluster.ReplicaID == "" {
+		// Container hostnames are unique per replica
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("CLUSTER_REPLICA_ID is not set and hostname is unavailable: %v", err)
+		}
+		cfg.Cluster.ReplicaID = hostname
+	}
+	// Tokens and URLs signed by one replica are checked by the others, so a
+	// per-process random secret would reject them
+	if cfg.Cluster.Enabled && cfg.WebSocket.ViewerTokenSecret == "" {
+		return nil, fmt.Errorf("VIEWER_TOKEN_SECRET must be set when CLUSTER_ENABLED is true")
+	}
+
+	// Validate passcode strength rules
+	switch cfg.Passcode.Charset {
+	case PasscodeCharsetNumeric, PasscodeCharsetAlphanumeric, PasscodeCharsetAny:
+	default:
+		return nil, fmt.Errorf("invalid PASSCODE_CHARSET: must be one of numeric, alphanumeric, any")
+	}
+	if cfg.Passcode.MinLength < 4 {
+		return nil, fmt.Errorf("PASSCODE_MIN_LENGTH must be at least 4")
+	}
+	if cfg.Passcode.MaxLength < cfg.Passcode.MinLength {
+		return nil, fmt.Errorf("PASSCODE_MAX_LENGTH must not be less than PASSCODE_MIN_LENGTH")
+	}
+	if cfg.Passcode.MaxLength > maxPasscodeBytes {
+		return nil, fmt.Errorf("PASSCODE_MAX_LENGTH must be at most %d", maxPasscodeBytes)
+	}
+	if cfg.Passcode.RecoveryTTL <= 0 {
+		return nil, fmt.Errorf("PASSCODE_RECOVERY_TTL_MINUTES must be positive")
+	}
+
+	// SMTP_FROM is required once a mail server is configured
+	if cfg.SMTP.Host != "" && cfg.SMTP.From == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
 	}
 
 	// Get timezone from EVENT_TIMEZONE or default to Asia/Tokyo
@@ -116,3 +513,42 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsTokens parses a comma separated list of "name:token" pairs into a
+// map from token to name
+func getEnvAsTokens(key string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, entry := range getEnvAsList(key, nil) {
+		name, token, found := strings.Cut(entry, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !found || name == "" || len(token) < 16 {
+			return nil, fmt.Errorf("invalid %s entry %q: use name:token with a token of at least 16 characters", key, name)
+		}
+		tokens[token] = name
+	}
+	return tokens, nil
+}
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SaveChatMessage stores a chat message against the reservation that is on
+// air at the time it was sent (NULL between sets)
+func (db *DB) SaveChatMessage(msg *ChatMessage) error {
+	query := `
+		INSERT INTO chat_messages (id, reservation_id, viewer_id, nickname, body, ip, created_at)
+		VALUES ($1, (
+			SELECT id FROM reservations
+			WHERE start_time <= $6 AND end_time > $6
+			ORDER BY start_time
+			LIMIT 1
+		), $2, $3, $4, $5, $6)
+	`
+
+	_, err := db.Exec(query, msg.ID, msg.ViewerID, msg.Nickname, msg.Body, msg.IP, msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save chat message: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) GetChatMessages(reservationID uuid.UUID) ([]ChatMessage, error) {
+	messages := []ChatMessage{}
+
+	query := `
+		SELECT id, reservation_id, viewer_id, nickname, body, ip, deleted_at, created_at
+		FROM chat_messages
+		WHERE reservation_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	if err := db.Select(&messages, query, reservationID); err != nil {
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (db *DB) GetChatMessage(id uuid.UUID) (*ChatMessage, error) {
+	var msg ChatMessage
+
+	query := `
+		SELECT id, reservation_id, viewer_id, nickname, body, ip, deleted_at, created_at
+		FROM chat_messages
+		WHERE id = $1
+	`
+
+	if err := db.Get(&msg, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("message not found")
+		}
+		return nil, fmt.Errorf("failed to get chat message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// MarkChatMessageDeleted hides a message from the stored chat log
+func (db *DB) MarkChatMessageDeleted(id uuid.UUID) error {
+	query := `UPDATE chat_messages SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+	if _, err := db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to delete chat message: %w", err)
+	}
+
+	return nil
+}
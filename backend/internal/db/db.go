@@ -23,11 +23,14 @@ type Config struct {
 	SSLMode  string
 }
 
-func New(cfg Config, logger *logrus.Logger) (*DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+// DSN returns the lib/pq connection string for the configuration
+func (cfg Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+}
 
-	db, err := sqlx.Connect("postgres", dsn)
+func New(cfg Config, logger *logrus.Logger) (*DB, error) {
+	db, err := sqlx.Connect("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -53,6 +56,200 @@ func (db *DB) Migrate() error {
 	queries := []string{
 		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`,
 		`CREATE EXTENSION IF NOT EXISTS "btree_gist"`,
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS contact_email VARCHAR(254)`,
+		`CREATE TABLE IF NOT EXISTS passcode_recovery_tokens (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			expires_at TIMESTAMPTZ NOT NULL,
+			used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_passcode_recovery_tokens_reservation ON passcode_recovery_tokens(reservation_id)`,
+		`CREATE TABLE IF NOT EXISTS chat_messages (
+			id UUID PRIMARY KEY,
+			reservation_id UUID REFERENCES reservations(id) ON DELETE CASCADE,
+			viewer_id VARCHAR(64) NOT NULL,
+			nickname VARCHAR(30) NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_chat_messages_reservation ON chat_messages(reservation_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS reaction_stats (
+			reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+			minute TIMESTAMPTZ NOT NULL,
+			reaction VARCHAR(32) NOT NULL,
+			count INTEGER NOT NULL,
+			PRIMARY KEY (reservation_id, minute, reaction)
+		)`,
+		`ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS ip VARCHAR(45)`,
+		`ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+		`CREATE TABLE IF NOT EXISTS chat_bans (
+			id UUID PRIMARY KEY,
+			viewer_id VARCHAR(64),
+			ip VARCHAR(45),
+			reason TEXT NOT NULL DEFAULT '',
+			expires_at TIMESTAMPTZ,
+			created_by VARCHAR(100) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			revoked_at TIMESTAMPTZ,
+			CHECK (viewer_id IS NOT NULL OR ip IS NOT NULL)
+		)`,
+		`CREATE TABLE IF NOT EXISTS moderation_actions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			action VARCHAR(32) NOT NULL,
+			moderator VARCHAR(100) NOT NULL,
+			message_id UUID,
+			ban_id UUID,
+			target_viewer_id VARCHAR(64),
+			target_ip VARCHAR(45),
+			details TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_moderation_actions_created ON moderation_actions(created_at)`,
+		`CREATE TABLE IF NOT EXISTS slot_holds (
+			id UUID PRIMARY KEY,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			start_time TIMESTAMPTZ NOT NULL,
+			end_time TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			CHECK (start_time < end_time)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_slot_holds_expires ON slot_holds(expires_at)`,
+		// B2B lineups: the display name grows beyond one DJ name, which
+		// requires recreating the view that selects it. Only done once, since
+		// the ALTER locks reservations and other replicas read the view.
+		`DO $$
+		BEGIN
+			IF (SELECT character_maximum_length FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = 'reservations' AND column_name = 'dj_name') < 500 THEN
+				DROP VIEW IF EXISTS current_next_dj;
+				ALTER TABLE reservations ALTER COLUMN dj_name TYPE VARCHAR(500);
+			END IF;
+		END
+		$$`,
+		`CREATE OR REPLACE VIEW current_next_dj AS
+		WITH current_dj AS (
+			SELECT id, dj_name, start_time, end_time
+			FROM reservations
+			WHERE start_time <= CURRENT_TIMESTAMP AND end_time > CURRENT_TIMESTAMP
+			ORDER BY start_time
+			LIMIT 1
+		),
+		next_dj AS (
+			SELECT id, dj_name, start_time, end_time
+			FROM reservations
+			WHERE start_time > CURRENT_TIMESTAMP
+			ORDER BY start_time
+			LIMIT 1
+		)
+		SELECT
+			current_dj.dj_name AS current_dj_name,
+			current_dj.start_time AS current_start_time,
+			current_dj.end_time AS current_end_time,
+			next_dj.dj_name AS next_dj_name,
+			next_dj.start_time AS next_start_time,
+			next_dj.end_time AS next_end_time,
+			current_dj.id AS current_id,
+			next_dj.id AS next_id
+		FROM (SELECT 1) dummy
+		LEFT JOIN current_dj ON true
+		LEFT JOIN next_dj ON true`,
+		`CREATE TABLE IF NOT EXISTS reservation_performers (
+			reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+			position SMALLINT NOT NULL CHECK (position > 0),
+			dj_name VARCHAR(100) NOT NULL,
+			passcode VARCHAR(60),
+			PRIMARY KEY (reservation_id, position)
+		)`,
+		`INSERT INTO reservation_performers (reservation_id, position, dj_name)
+		SELECT id, 1, dj_name FROM reservations r
+		WHERE NOT EXISTS (SELECT 1 FROM reservation_performers p WHERE p.reservation_id = r.id)`,
+		`CREATE TABLE IF NOT EXISTS waitlist_entries (
+			id UUID PRIMARY KEY,
+			dj_name VARCHAR(100) NOT NULL,
+			contact_email VARCHAR(254),
+			webhook_url VARCHAR(2048),
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			start_time TIMESTAMPTZ NOT NULL,
+			end_time TIMESTAMPTZ NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'waiting',
+			offered_at TIMESTAMPTZ,
+			offer_expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			CHECK (start_time < end_time),
+			CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'cancelled'))
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_waitlist_entries_status ON waitlist_entries(status, created_at)`,
+		`CREATE TABLE IF NOT EXISTS dj_profiles (
+			id UUID PRIMARY KEY,
+			display_name VARCHAR(100) NOT NULL,
+			bio TEXT NOT NULL DEFAULT '',
+			genres TEXT[] NOT NULL DEFAULT '{}',
+			links TEXT[] NOT NULL DEFAULT '{}',
+			avatar_file VARCHAR(255),
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`ALTER TABLE reservation_performers ADD COLUMN IF NOT EXISTS profile_id UUID REFERENCES dj_profiles(id) ON DELETE SET NULL`,
+		`CREATE TABLE IF NOT EXISTS stream_sessions (
+			id UUID PRIMARY KEY,
+			reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			ended_at TIMESTAMPTZ,
+			rtmp_key VARCHAR(255) NOT NULL,
+			viewer_count INTEGER NOT NULL DEFAULT 0,
+			peak_viewers INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_stream_sessions_reservation ON stream_sessions(reservation_id, started_at)`,
+		`CREATE TABLE IF NOT EXISTS recordings (
+			id UUID PRIMARY KEY,
+			reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+			session_id UUID NOT NULL REFERENCES stream_sessions(id) ON DELETE CASCADE,
+			path_name VARCHAR(255) NOT NULL,
+			file VARCHAR(1024) NOT NULL UNIQUE,
+			started_at TIMESTAMPTZ NOT NULL,
+			duration_seconds DOUBLE PRECISION,
+			size_bytes BIGINT,
+			completed_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_recordings_reservation ON recordings(reservation_id, started_at)`,
+		`ALTER TABLE stream_sessions ADD COLUMN IF NOT EXISTS vod_public BOOLEAN NOT NULL DEFAULT TRUE`,
+		`CREATE TABLE IF NOT EXISTS tracks (
+			id UUID PRIMARY KEY,
+			reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+			artist VARCHAR(200) NOT NULL,
+			title VARCHAR(200) NOT NULL,
+			played_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_tracks_reservation ON tracks(reservation_id, played_at)`,
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS end_handled_at TIMESTAMPTZ`,
+		`CREATE TABLE IF NOT EXISTS dj_presence (
+			reservation_id UUID PRIMARY KEY REFERENCES reservations(id) ON DELETE CASCADE,
+			last_seen_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS restream_targets (
+			id UUID PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			url TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			status VARCHAR(20) NOT NULL DEFAULT 'stopped',
+			restart_count INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			owner VARCHAR(255),
+			heartbeat_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS consent_record BOOLEAN NOT NULL DEFAULT TRUE`,
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS consent_restream BOOLEAN NOT NULL DEFAULT TRUE`,
+		`ALTER TABLE reservations ADD COLUMN IF NOT EXISTS consent_public_vod BOOLEAN NOT NULL DEFAULT TRUE`,
+		`ALTER TABLE slot_holds ADD COLUMN IF NOT EXISTS client_ip VARCHAR(45)`,
+		`CREATE INDEX IF NOT EXISTS idx_slot_holds_client_ip ON slot_holds(client_ip)`,
 	}
 
 	for _, query := range queries {
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// slotLockKey is the advisory lock that serializes hold and reservation
+// writes, so that a hold and a reservation cannot claim the same time at once
+const slotLockKey = 727001
+
+func lockSlots(tx *sqlx.Tx) error {
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", slotLockKey); err != nil {
+		return fmt.Errorf("failed to lock slots: %w", err)
+	}
+	return nil
+}
+
+// CreateSlotHold places a temporary hold on a time range for clientIP. It
+// fails with "time conflict" if the range overlaps a reservation, with "slot
+// held" if it overlaps another active hold and with "hold limit" if the
+// address would exceed maxHolds active holds or maxHeld held time in total.
+// Zero limits are not enforced.
+func (db *DB) CreateSlotHold(tokenHash, clientIP string, startTime, endTime, expiresAt time.Time, maxHolds int, maxHeld time.Duration) (*SlotHold, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockSlots(tx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var reserved bool
+	query := `SELECT EXISTS (SELECT 1 FROM reservations WHERE start_time < $2 AND end_time > $1)`
+	if err := tx.Get(&reserved, query, startTime, endTime); err != nil {
+		return nil, fmt.Errorf("failed to check reservations: %w", err)
+	}
+	if reserved {
+		return nil, fmt.Errorf("time conflict")
+	}
+
+	if err := checkNoActiveHold(tx, startTime, endTime, now); err != nil {
+		return nil, err
+	}
+
+	var active struct {
+		Count   int     `db:"count"`
+		Seconds float64 `db:"seconds"`
+	}
+	query = `
+		SELECT COUNT(*) AS count, COALESCE(SUM(EXTRACT(EPOCH FROM end_time - start_time)), 0) AS seconds
+		FROM slot_holds
+		WHERE client_ip = $1 AND expires_at > $2
+	`
+	if err := tx.Get(&active, query, clientIP, now); err != nil {
+		return nil, fmt.Errorf("failed to count slot holds: %w", err)
+	}
+	held := time.Duration(active.Seconds*float64(time.Second)) + endTime.Sub(startTime)
+	if (maxHolds > 0 && active.Count >= maxHolds) || (maxHeld > 0 && held > maxHeld) {
+		return nil, fmt.Errorf("hold limit")
+	}
+
+	hold := SlotHold{
+		ID:        uuid.New(),
+		TokenHash: tokenHash,
+		StartTime: startTime,
+		EndTime:   endTime,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		ClientIP:  &clientIP,
+	}
+	query = `
+		INSERT INTO slot_holds (id, token_hash, start_time, end_time, expires_at, created_at, client_ip)
+		VALUES (:id, :token_hash, :start_time, :end_time, :expires_at, :created_at, :client_ip)
+	`
+	if _, err := tx.NamedExec(query, hold); err != nil {
+		return nil, fmt.Errorf("failed to create slot hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &hold, nil
+}
+
+func checkNoActiveHold(tx *sqlx.Tx, startTime, endTime, now time.Time) error {
+	var held bool
+	query := `SELECT EXISTS (SELECT 1 FROM slot_holds WHERE start_time < $2 AND end_time > $1 AND expires_at > $3)`
+	if err := tx.Get(&held, query, startTime, endTime, now); err != nil {
+		return fmt.Errorf("failed to check slot holds: %w", err)
+	}
+	if held {
+		return fmt.Errorf("slot held")
+	}
+	return nil
+}
+
+// consumeSlotHold deletes the active hold with the given token if it covers
+// the time range, failing with "invalid hold" otherwise
+func consumeSlotHold(tx *sqlx.Tx, tokenHash string, startTime, endTime, now time.Time) error {
+	var id uuid.UUID
+	query := `
+		DELETE FROM slot_holds
+		WHERE token_hash = $1 AND expires_at > $2 AND start_time <= $3 AND end_time >= $4
+		RETURNING id
+	`
+	if err := tx.Get(&id, query, tokenHash, now, startTime, endTime); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("invalid hold")
+		}
+		return fmt.Errorf("failed to consume slot hold: %w", err)
+	}
+	return nil
+}
+
+// GetActiveSlotHoldsInRange returns unexpired holds overlapping the range
+func (db *DB) GetActiveSlotHoldsInRange(startTime, endTime time.Time) ([]SlotHold, error) {
+	var holds []SlotHold
+
+	query := `
+		SELECT id, token_hash, start_time, end_time, expires_at, created_at, client_ip
+		FROM slot_holds
+		WHERE start_time < $2 AND end_time > $1 AND expires_at > $3
+		ORDER BY start_time
+	`
+
+	if err := db.Select(&holds, query, startTime, endTime, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to get slot holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// DeleteExpiredSlotHolds removes holds that expired before now and returns them
+func (db *DB) DeleteExpiredSlotHolds(now time.Time) ([]SlotHold, error) {
+	var holds []SlotHold
+
+	query := `
+		DELETE FROM slot_holds
+		WHERE expires_at <= $1
+		RETURNING id, token_hash, start_time, end_time, expires_at, created_at, client_ip
+	`
+
+	if err := db.Select(&holds, query, now); err != nil {
+		return nil, fmt.Errorf("failed to delete expired slot holds: %w", err)
+	}
+
+	return holds, nil
+}
@@ -2,16 +2,35 @@ package db
 
 import (
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"time"
 )
 
 type Reservation struct {
-	ID        uuid.UUID `db:"id"`
-	DJName    string    `db:"dj_name"`
-	StartTime time.Time `db:"start_time"`
-	EndTime   time.Time `db:"end_time"`
-	Passcode  string    `db:"passcode"`
-	CreatedAt time.Time `db:"created_at"`
+	ID           uuid.UUID `db:"id"`
+	DJName       string    `db:"dj_name"`
+	StartTime    time.Time `db:"start_time"`
+	EndTime      time.Time `db:"end_time"`
+	Passcode     string    `db:"passcode"`
+	ContactEmail *string   `db:"contact_email"`
+	CreatedAt    time.Time `db:"created_at"`
+	Consent
+}
+
+// Consent is what the DJs of a reservation allow for their set
+type Consent struct {
+	Record    bool `db:"consent_record"`
+	Restream  bool `db:"consent_restream"`
+	PublicVOD bool `db:"consent_public_vod"`
+}
+
+type PasscodeRecoveryToken struct {
+	ID            uuid.UUID  `db:"id"`
+	ReservationID uuid.UUID  `db:"reservation_id"`
+	TokenHash     string     `db:"token_hash"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+	UsedAt        *time.Time `db:"used_at"`
+	CreatedAt     time.Time  `db:"created_at"`
 }
 
 type StreamSession struct {
@@ -22,6 +41,59 @@ type StreamSession struct {
 	RTMPKey       string     `db:"rtmp_key"`
 	ViewerCount   int        `db:"viewer_count"`
 	PeakViewers   int        `db:"peak_viewers"`
+	VODPublic     bool       `db:"vod_public"`
+}
+
+// VOD is a finished stream session of a reservation, played back from its
+// recordings. PathName is the MediaMTX path that was recorded.
+type VOD struct {
+	ID            uuid.UUID `db:"id"`
+	ReservationID uuid.UUID `db:"reservation_id"`
+	DJName        string    `db:"dj_name"`
+	PathName      string    `db:"rtmp_key"`
+	StartedAt     time.Time `db:"started_at"`
+	EndedAt       time.Time `db:"ended_at"`
+	Public        bool      `db:"vod_public"`
+}
+
+// Recording is one segment file written by MediaMTX. File is relative to
+// the media directory. Duration and size are known once it is complete.
+type Recording struct {
+	ID              uuid.UUID  `db:"id"`
+	ReservationID   *uuid.UUID `db:"reservation_id"`
+	SessionID       uuid.UUID  `db:"session_id"`
+	PathName        string     `db:"path_name"`
+	File            string     `db:"file"`
+	StartedAt       time.Time  `db:"started_at"`
+	DurationSeconds *float64   `db:"duration_seconds"`
+	SizeBytes       *int64     `db:"size_bytes"`
+	CompletedAt     *time.Time `db:"completed_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+// Track is one entry of a reservation's tracklist
+type Track struct {
+	ID            uuid.UUID `db:"id"`
+	ReservationID uuid.UUID `db:"reservation_id"`
+	Artist        string    `db:"artist"`
+	Title         string    `db:"title"`
+	PlayedAt      time.Time `db:"played_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// RestreamTarget is an RTMP destination the live stream is forwarded to
+type RestreamTarget struct {
+	ID           uuid.UUID  `db:"id"`
+	Name         string     `db:"name"`
+	URL          string     `db:"url"`
+	Enabled      bool       `db:"enabled"`
+	Status       string     `db:"status"`
+	RestartCount int        `db:"restart_count"`
+	LastError    *string    `db:"last_error"`
+	Owner        *string    `db:"owner"`
+	HeartbeatAt  *time.Time `db:"heartbeat_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
 }
 
 type ViewerStats struct {
@@ -38,4 +110,101 @@ type CurrentNextDJ struct {
 	NextDJName       *string    `db:"next_dj_name"`
 	NextStartTime    *time.Time `db:"next_start_time"`
 	NextEndTime      *time.Time `db:"next_end_time"`
+	CurrentID        *uuid.UUID `db:"current_id"`
+	NextID           *uuid.UUID `db:"next_id"`
+}
+
+// Performer is one DJ of a reservation's lineup, in playing order. B2B
+// performers after the first have their own passcode.
+type Performer struct {
+	ReservationID uuid.UUID  `db:"reservation_id"`
+	Position      int        `db:"position"`
+	DJName        string     `db:"dj_name"`
+	ProfileID     *uuid.UUID `db:"profile_id"`
+}
+
+// DJProfile is the public profile of a DJ. It is edited with a secret token
+// of which only the SHA-256 hash is stored. AvatarFile is relative to the
+// media directory.
+type DJProfile struct {
+	ID          uuid.UUID      `db:"id"`
+	DisplayName string         `db:"display_name"`
+	Bio         string         `db:"bio"`
+	Genres      pq.StringArray `db:"genres"`
+	Links       pq.StringArray `db:"links"`
+	AvatarFile  *string        `db:"avatar_file"`
+	TokenHash   string         `db:"token_hash"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+type ChatMessage struct {
+	ID            uuid.UUID  `db:"id"`
+	ReservationID *uuid.UUID `db:"reservation_id"`
+	ViewerID      string     `db:"viewer_id"`
+	Nickname      string     `db:"nickname"`
+	Body          string     `db:"body"`
+	IP            *string    `db:"ip"`
+	DeletedAt     *time.Time `db:"deleted_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+type ReactionStat struct {
+	ReservationID uuid.UUID `db:"reservation_id"`
+	Minute        time.Time `db:"minute"`
+	Reaction      string    `db:"reaction"`
+	Count         int       `db:"count"`
+}
+
+type ChatBan struct {
+	ID        uuid.UUID  `db:"id"`
+	ViewerID  *string    `db:"viewer_id"`
+	IP        *string    `db:"ip"`
+	Reason    string     `db:"reason"`
+	ExpiresAt *time.Time `db:"expires_at"`
+	CreatedBy string     `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type ModerationAction struct {
+	ID             uuid.UUID  `db:"id"`
+	Action         string     `db:"action"`
+	Moderator      string     `db:"moderator"`
+	MessageID      *uuid.UUID `db:"message_id"`
+	BanID          *uuid.UUID `db:"ban_id"`
+	TargetViewerID *string    `db:"target_viewer_id"`
+	TargetIP       *string    `db:"target_ip"`
+	Details        string     `db:"details"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// SlotHold reserves a time range for a few minutes while a DJ fills in the
+// booking form. Only the SHA-256 hash of the hold token is stored.
+type SlotHold struct {
+	ID        uuid.UUID `db:"id"`
+	TokenHash string    `db:"token_hash"`
+	StartTime time.Time `db:"start_time"`
+	EndTime   time.Time `db:"end_time"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+	// ClientIP is the address that placed the hold; waitlist offers have none
+	ClientIP *string `db:"client_ip"`
+}
+
+// WaitlistEntry is a DJ waiting for a time range to free up. When it does,
+// the entry is offered an exclusive slot hold whose token is the entry's
+// own token (only its SHA-256 hash is stored).
+type WaitlistEntry struct {
+	ID             uuid.UUID  `db:"id"`
+	DJName         string     `db:"dj_name"`
+	ContactEmail   *string    `db:"contact_email"`
+	WebhookURL     *string    `db:"webhook_url"`
+	TokenHash      string     `db:"token_hash"`
+	StartTime      time.Time  `db:"start_time"`
+	EndTime        time.Time  `db:"end_time"`
+	Status         string     `db:"status"`
+	OfferedAt      *time.Time `db:"offered_at"`
+	OfferExpiresAt *time.Time `db:"offer_expires_at"`
+	CreatedAt      time.Time  `db:"created_at"`
 }
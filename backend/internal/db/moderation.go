@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CreateChatBan saves a ban together with its audit log entry
+func (db *DB) CreateChatBan(ban *ChatBan, action *ModerationAction) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO chat_bans (id, viewer_id, ip, reason, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = tx.Exec(query, ban.ID, ban.ViewerID, ban.IP, ban.Reason, ban.ExpiresAt, ban.CreatedBy, ban.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create chat ban: %w", err)
+	}
+
+	_, err = tx.Exec(insertModerationActionQuery, action.Action, action.Moderator, action.MessageID, action.BanID,
+		action.TargetViewerID, action.TargetIP, action.Details, action.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert moderation action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) RevokeChatBan(id uuid.UUID) error {
+	query := `UPDATE chat_bans SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to revoke chat ban: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveChatBans returns bans that are neither revoked nor expired
+func (db *DB) GetActiveChatBans() ([]ChatBan, error) {
+	bans := []ChatBan{}
+
+	query := `
+		SELECT id, viewer_id, ip, reason, expires_at, created_by, created_at, revoked_at
+		FROM chat_bans
+		WHERE revoked_at IS NULL AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		ORDER BY created_at
+	`
+
+	if err := db.Select(&bans, query); err != nil {
+		return nil, fmt.Errorf("failed to get chat bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+const insertModerationActionQuery = `
+	INSERT INTO moderation_actions (action, moderator, message_id, ban_id, target_viewer_id, target_ip, details, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+func (db *DB) InsertModerationAction(action *ModerationAction) error {
+	_, err := db.Exec(insertModerationActionQuery, action.Action, action.Moderator, action.MessageID, action.BanID,
+		action.TargetViewerID, action.TargetIP, action.Details, action.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert moderation action: %w", err)
+	}
+
+	return nil
+}
+
+// GetModerationActions returns the most recent audit log entries, newest first
+func (db *DB) GetModerationActions(limit int) ([]ModerationAction, error) {
+	actions := []ModerationAction{}
+
+	query := `
+		SELECT id, action, moderator, message_id, ban_id, target_viewer_id, target_ip, details, created_at
+		FROM moderation_actions
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+
+	if err := db.Select(&actions, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get moderation actions: %w", err)
+	}
+
+	return actions, nil
+}
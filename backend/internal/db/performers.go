@@ -0,0 +1,84 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPerformers is the largest back-to-back lineup a reservation can carry
+const MaxPerformers = 4
+
+// performerSeparator joins performer names into the reservation's display name
+const performerSeparator = " b2b "
+
+// NewPerformer is a performer of a reservation being created
+type NewPerformer struct {
+	DJName    string
+	Passcode  string
+	ProfileID *uuid.UUID
+}
+
+// PerformerDisplayName renders a lineup as one name, e.g. "A b2b B"
+func PerformerDisplayName(names []string) string {
+	return strings.Join(names, performerSeparator)
+}
+
+// insertPerformers stores the lineup of a new reservation. The first
+// performer is the one who booked and uses the reservation passcode; the
+// others get their own passcode hashes.
+func insertPerformers(tx *sqlx.Tx, reservationID uuid.UUID, performers []NewPerformer) error {
+	for i, performer := range performers {
+		var passcode *string
+		if i > 0 {
+			hashed, err := bcrypt.GenerateFromPassword([]byte(performer.Passcode), bcrypt.DefaultCost)
+			if err != nil {
+				return fmt.Errorf("failed to hash passcode: %w", err)
+			}
+			hash := string(hashed)
+			passcode = &hash
+		}
+
+		query := `
+			INSERT INTO reservation_performers (reservation_id, position, dj_name, passcode, profile_id)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		if _, err := tx.Exec(query, reservationID, i+1, performer.DJName, passcode, performer.ProfileID); err != nil {
+			return fmt.Errorf("failed to create performer: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetPerformers returns the ordered lineups of the given reservations
+func (db *DB) GetPerformers(reservationIDs []uuid.UUID) (map[uuid.UUID][]Performer, error) {
+	lineups := make(map[uuid.UUID][]Performer, len(reservationIDs))
+	if len(reservationIDs) == 0 {
+		return lineups, nil
+	}
+
+	ids := make([]string, len(reservationIDs))
+	for i, id := range reservationIDs {
+		ids[i] = id.String()
+	}
+
+	var performers []Performer
+	query := `
+		SELECT reservation_id, position, dj_name, profile_id
+		FROM reservation_performers
+		WHERE reservation_id = ANY($1::uuid[])
+		ORDER BY reservation_id, position
+	`
+	if err := db.Select(&performers, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get performers: %w", err)
+	}
+
+	for _, performer := range performers {
+		lineups[performer.ReservationID] = append(lineups[performer.ReservationID], performer)
+	}
+	return lineups, nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TouchDJPresence records that the DJ of a reservation was connected at seenAt
+func (db *DB) TouchDJPresence(reservationID uuid.UUID, seenAt time.Time) error {
+	query := `
+		INSERT INTO dj_presence (reservation_id, last_seen_at)
+		VALUES ($1, $2)
+		ON CONFLICT (reservation_id)
+		DO UPDATE SET last_seen_at = GREATEST(dj_presence.last_seen_at, EXCLUDED.last_seen_at)
+	`
+	if _, err := db.Exec(query, reservationID, seenAt); err != nil {
+		return fmt.Errorf("failed to update dj presence: %w", err)
+	}
+	return nil
+}
+
+// IsDJPresent reports whether the DJ of a reservation was connected at or after since
+func (db *DB) IsDJPresent(reservationID uuid.UUID, since time.Time) (bool, error) {
+	var present bool
+	query := `SELECT EXISTS (SELECT 1 FROM dj_presence WHERE reservation_id = $1 AND last_seen_at >= $2)`
+	if err := db.Get(&present, query, reservationID, since); err != nil {
+		return false, fmt.Errorf("failed to get dj presence: %w", err)
+	}
+	return present, nil
+}
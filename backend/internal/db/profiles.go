@@ -0,0 +1,139 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const profileColumns = `id, display_name, bio, genres, links, avatar_file, token_hash, created_at, updated_at`
+
+// CreateDJProfile stores a new profile
+func (db *DB) CreateDJProfile(displayName, bio string, genres, links []string, tokenHash string) (*DJProfile, error) {
+	now := time.Now()
+	profile := DJProfile{
+		ID:          uuid.New(),
+		DisplayName: displayName,
+		Bio:         bio,
+		Genres:      pq.StringArray(genres),
+		Links:       pq.StringArray(links),
+		TokenHash:   tokenHash,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	query := `
+		INSERT INTO dj_profiles (id, display_name, bio, genres, links, token_hash, created_at, updated_at)
+		VALUES (:id, :display_name, :bio, :genres, :links, :token_hash, :created_at, :updated_at)
+	`
+	if _, err := db.NamedExec(query, profile); err != nil {
+		return nil, fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// GetDJProfile returns a profile by ID
+func (db *DB) GetDJProfile(id uuid.UUID) (*DJProfile, error) {
+	var profile DJProfile
+	query := `SELECT ` + profileColumns + ` FROM dj_profiles WHERE id = $1`
+	if err := db.Get(&profile, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile not found")
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// GetDJProfileByToken returns the profile a token belongs to, which proves
+// that the caller owns it
+func (db *DB) GetDJProfileByToken(tokenHash string) (*DJProfile, error) {
+	var profile DJProfile
+	query := `SELECT ` + profileColumns + ` FROM dj_profiles WHERE token_hash = $1`
+	if err := db.Get(&profile, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile not found")
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// GetDJProfiles returns the given profiles by ID
+func (db *DB) GetDJProfiles(ids []uuid.UUID) (map[uuid.UUID]DJProfile, error) {
+	profiles := make(map[uuid.UUID]DJProfile, len(ids))
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+
+	var rows []DJProfile
+	query := `SELECT ` + profileColumns + ` FROM dj_profiles WHERE id = ANY($1::uuid[])`
+	if err := db.Select(&rows, query, pq.Array(keys)); err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+
+	for _, profile := range rows {
+		profiles[profile.ID] = profile
+	}
+	return profiles, nil
+}
+
+// UpdateDJProfile replaces the text fields of a profile. It fails with
+// "profile not found" or "invalid token".
+func (db *DB) UpdateDJProfile(id uuid.UUID, tokenHash, displayName, bio string, genres, links []string) (*DJProfile, error) {
+	var profile DJProfile
+	query := `
+		UPDATE dj_profiles
+		SET display_name = $3, bio = $4, genres = $5, links = $6, updated_at = $7
+		WHERE id = $1 AND token_hash = $2
+		RETURNING ` + profileColumns
+	err := db.Get(&profile, query, id, tokenHash, displayName, bio, pq.StringArray(genres), pq.StringArray(links), time.Now())
+	if err == sql.ErrNoRows {
+		return nil, db.profileAuthError(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// SetDJProfileAvatar records a newly stored avatar file and returns the
+// updated profile. It fails with "profile not found" or "invalid token".
+func (db *DB) SetDJProfileAvatar(id uuid.UUID, tokenHash, avatarFile string) (*DJProfile, error) {
+	var profile DJProfile
+	query := `
+		UPDATE dj_profiles
+		SET avatar_file = $3, updated_at = $4
+		WHERE id = $1 AND token_hash = $2
+		RETURNING ` + profileColumns
+	err := db.Get(&profile, query, id, tokenHash, avatarFile, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, db.profileAuthError(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile avatar: %w", err)
+	}
+	return &profile, nil
+}
+
+// profileAuthError tells a missing profile from a wrong token after an
+// update matched no row
+func (db *DB) profileAuthError(id uuid.UUID) error {
+	var exists bool
+	if err := db.Get(&exists, "SELECT EXISTS (SELECT 1 FROM dj_profiles WHERE id = $1)", id); err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("profile not found")
+	}
+	return fmt.Errorf("invalid token")
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AddReactionTotals adds one minute of reaction counts to the reservation
+// that was on air during that minute. Minutes between sets are discarded.
+func (db *DB) AddReactionTotals(minute time.Time, counts map[string]int) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO reaction_stats (reservation_id, minute, reaction, count)
+		SELECT id, $1, $2, $3 FROM reservations
+		WHERE start_time <= $1 AND end_time > $1
+		ORDER BY start_time
+		LIMIT 1
+		ON CONFLICT (reservation_id, minute, reaction)
+		DO UPDATE SET count = reaction_stats.count + EXCLUDED.count
+	`
+
+	for reaction, count := range counts {
+		if _, err := tx.Exec(query, minute, reaction, count); err != nil {
+			return fmt.Errorf("failed to add reaction totals: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) GetReactionStats(reservationID uuid.UUID) ([]ReactionStat, error) {
+	stats := []ReactionStat{}
+
+	query := `
+		SELECT reservation_id, minute, reaction, count
+		FROM reaction_stats
+		WHERE reservation_id = $1
+		ORDER BY minute, reaction
+	`
+
+	if err := db.Select(&stats, query, reservationID); err != nil {
+		return nil, fmt.Errorf("failed to get reaction stats: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionGap is the longest pause between two segments of one stream
+// session. A publisher that comes back later starts a new session.
+const sessionGap = 30 * time.Second
+
+const recordingColumns = `id, reservation_id, session_id, path_name, file, started_at,
+	duration_seconds, size_bytes, completed_at, created_at`
+
+// CreateRecording registers a segment that MediaMTX started writing. It is
+// attached to the reservation on air at startedAt and to the stream session
+// whose last segment is still open or ended within sessionGap, or to a new
+// session otherwise. New sessions are public unless the reservation's DJs
+// withdrew their consent to public VOD.
+func (db *DB) CreateRecording(pathName, file string, startedAt time.Time) (*Recording, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var reservationID *uuid.UUID
+	var onAir struct {
+		ID        uuid.UUID `db:"id"`
+		PublicVOD bool      `db:"consent_public_vod"`
+	}
+	vodPublic := true
+	query := `SELECT id, consent_public_vod FROM reservations WHERE start_time <= $1 AND end_time > $1`
+	err = tx.Get(&onAir, query, startedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find reservation: %w", err)
+	}
+	if err == nil {
+		reservationID = &onAir.ID
+		vodPublic = onAir.PublicVOD
+	}
+
+	var sessionID uuid.UUID
+	query = `
+		SELECT s.id
+		FROM stream_sessions s
+		JOIN recordings r ON r.session_id = s.id
+		WHERE s.rtmp_key = $1 AND s.reservation_id IS NOT DISTINCT FROM $2
+		AND (r.completed_at IS NULL OR r.completed_at >= $3)
+		ORDER BY r.started_at DESC
+		LIMIT 1
+	`
+	err = tx.Get(&sessionID, query, pathName, reservationID, startedAt.Add(-sessionGap))
+	if err == sql.ErrNoRows {
+		sessionID = uuid.New()
+		query = `
+			INSERT INTO stream_sessions (id, reservation_id, started_at, rtmp_key, vod_public)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		if _, err := tx.Exec(query, sessionID, reservationID, startedAt, pathName, vodPublic); err != nil {
+			return nil, fmt.Errorf("failed to create stream session: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find stream session: %w", err)
+	}
+
+	recording := Recording{
+		ID:            uuid.New(),
+		ReservationID: reservationID,
+		SessionID:     sessionID,
+		PathName:      pathName,
+		File:          file,
+		StartedAt:     startedAt,
+		CreatedAt:     time.Now(),
+	}
+	query = `
+		INSERT INTO recordings (id, reservation_id, session_id, path_name, file, started_at, created_at)
+		VALUES (:id, :reservation_id, :session_id, :path_name, :file, :started_at, :created_at)
+		ON CONFLICT (file) DO NOTHING
+	`
+	if _, err := tx.NamedExec(query, recording); err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &recording, nil
+}
+
+// CompleteRecording stores the duration and size of a finished segment and
+// extends its stream session. It fails with "recording not found" if the
+// segment was never registered.
+func (db *DB) CompleteRecording(file string, durationSeconds float64, sizeBytes *int64, completedAt time.Time) (*Recording, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var recording Recording
+	query := `
+		UPDATE recordings
+		SET duration_seconds = $2, size_bytes = $3, completed_at = $4
+		WHERE file = $1
+		RETURNING ` + recordingColumns
+	if err := tx.Get(&recording, query, file, durationSeconds, sizeBytes, completedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("recording not found")
+		}
+		return nil, fmt.Errorf("failed to complete recording: %w", err)
+	}
+
+	endedAt := recording.StartedAt.Add(time.Duration(durationSeconds * float64(time.Second)))
+	query = `UPDATE stream_sessions SET ended_at = GREATEST(ended_at, $2) WHERE id = $1`
+	if _, err := tx.Exec(query, recording.SessionID, endedAt); err != nil {
+		return nil, fmt.Errorf("failed to update stream session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &recording, nil
+}
+
+// GetRecordings returns the segments recorded during a reservation in order
+func (db *DB) GetRecordings(reservationID uuid.UUID) ([]Recording, error) {
+	var recordings []Recording
+
+	query := `SELECT ` + recordingColumns + ` FROM recordings WHERE reservation_id = $1 ORDER BY started_at`
+	if err := db.Select(&recordings, query, reservationID); err != nil {
+		return nil, fmt.Errorf("failed to get recordings: %w", err)
+	}
+
+	return recordings, nil
+}
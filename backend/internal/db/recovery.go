@@ -0,0 +1,85 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (db *DB) CreatePasscodeRecoveryToken(reservationID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO passcode_recovery_tokens (id, reservation_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := db.Exec(query, uuid.New(), reservationID, tokenHash, expiresAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create recovery token: %w", err)
+	}
+
+	return nil
+}
+
+// HasRecentRecoveryToken reports whether a recovery token was issued for the
+// reservation after the given time, so repeated requests don't flood the inbox
+func (db *DB) HasRecentRecoveryToken(reservationID uuid.UUID, since time.Time) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM passcode_recovery_tokens WHERE reservation_id = $1 AND created_at > $2)`
+
+	if err := db.Get(&exists, query, reservationID, since); err != nil {
+		return false, fmt.Errorf("failed to check recovery tokens: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ResetPasscodeWithToken consumes an unused, unexpired recovery token and
+// replaces the reservation's passcode. All other outstanding tokens for the
+// reservation are invalidated at the same time.
+func (db *DB) ResetPasscodeWithToken(reservationID uuid.UUID, tokenHash, newPasscode string) error {
+	hashedPasscode, err := bcrypt.GenerateFromPassword([]byte(newPasscode), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash passcode: %w", err)
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var token PasscodeRecoveryToken
+	query := `
+		SELECT id, reservation_id, token_hash, expires_at, used_at, created_at
+		FROM passcode_recovery_tokens
+		WHERE reservation_id = $1 AND token_hash = $2
+		FOR UPDATE
+	`
+	if err := tx.Get(&token, query, reservationID, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("invalid recovery token")
+		}
+		return fmt.Errorf("failed to get recovery token: %w", err)
+	}
+
+	if token.UsedAt != nil || !time.Now().Before(token.ExpiresAt) {
+		return fmt.Errorf("invalid recovery token")
+	}
+
+	if _, err := tx.Exec("UPDATE reservations SET passcode = $1 WHERE id = $2", string(hashedPasscode), reservationID); err != nil {
+		return fmt.Errorf("failed to update passcode: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE passcode_recovery_tokens SET used_at = $1 WHERE reservation_id = $2 AND used_at IS NULL", time.Now(), reservationID); err != nil {
+		return fmt.Errorf("failed to invalidate recovery tokens: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -14,7 +14,7 @@ func (db *DB) GetReservations() ([]Reservation, error) {
 
 	// Get all reservations ordered by start time
 	query := `
-		SELECT id, dj_name, start_time, end_time, passcode, created_at
+		SELECT id, dj_name, start_time, end_time, passcode, consent_record, consent_restream, consent_public_vod, created_at
 		FROM reservations
 		ORDER BY start_time
 	`
@@ -27,35 +27,107 @@ func (db *DB) GetReservations() ([]Reservation, error) {
 	return reservations, nil
 }
 
-func (db *DB) CreateReservation(djName string, startTime, endTime time.Time, passcode string) (*Reservation, error) {
-	hashedPasscode, err := bcrypt.GenerateFromPassword([]byte(passcode), bcrypt.DefaultCost)
+func (db *DB) GetReservation(id uuid.UUID) (*Reservation, error) {
+	var reservation Reservation
+
+	query := `
+		SELECT id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at
+		FROM reservations
+		WHERE id = $1
+	`
+
+	err := db.Get(&reservation, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reservation not found")
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// CreateReservation inserts a reservation with its ordered performers. The
+// first performer's passcode becomes the reservation passcode, and the
+// reservation's DJ name is the combined display name of all performers.
+// With a hold token hash the matching slot hold is consumed ("invalid hold"
+// if it expired or does not cover the range); without one the range must
+// not be held by someone else ("slot held").
+func (db *DB) CreateReservation(performers []NewPerformer, startTime, endTime time.Time, contactEmail *string, consent Consent, holdTokenHash string) (*Reservation, error) {
+	if len(performers) == 0 || len(performers) > MaxPerformers {
+		return nil, fmt.Errorf("invalid performer count")
+	}
+
+	hashedPasscode, err := bcrypt.GenerateFromPassword([]byte(performers[0].Passcode), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash passcode: %w", err)
 	}
 
+	names := make([]string, len(performers))
+	for i, performer := range performers {
+		names[i] = performer.DJName
+	}
+
 	reservation := Reservation{
-		ID:        uuid.New(),
-		DJName:    djName,
-		StartTime: startTime,
-		EndTime:   endTime,
-		Passcode:  string(hashedPasscode),
-		CreatedAt: time.Now(),
+		ID:           uuid.New(),
+		DJName:       PerformerDisplayName(names),
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Passcode:     string(hashedPasscode),
+		ContactEmail: contactEmail,
+		CreatedAt:    time.Now(),
+		Consent:      consent,
 	}
 
 	query := `
-		INSERT INTO reservations (id, dj_name, start_time, end_time, passcode, created_at)
-		VALUES (:id, :dj_name, :start_time, :end_time, :passcode, :created_at)
+		INSERT INTO reservations (id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at)
+		VALUES (:id, :dj_name, :start_time, :end_time, :passcode, :contact_email, :consent_record, :consent_restream, :consent_public_vod, :created_at)
 	`
 
-	_, execErr := db.NamedExec(query, reservation)
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockSlots(tx); err != nil {
+		return nil, err
+	}
+
+	if holdTokenHash != "" {
+		err = consumeSlotHold(tx, holdTokenHash, startTime, endTime, reservation.CreatedAt)
+		if err == nil {
+			// Holds offered to the waitlist share the entry's token
+			_, err = tx.Exec("UPDATE waitlist_entries SET status = $1 WHERE token_hash = $2 AND status = $3",
+				WaitlistClaimed, holdTokenHash, WaitlistOffered)
+		}
+	} else {
+		err = checkNoActiveHold(tx, startTime, endTime, reservation.CreatedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, execErr := tx.NamedExec(query, reservation)
 	if execErr != nil {
 		return nil, fmt.Errorf("failed to create reservation: %w", execErr)
 	}
 
+	if err := insertPerformers(tx, reservation.ID, performers); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &reservation, nil
 }
 
-func (db *DB) DeleteReservation(id uuid.UUID, passcode string) error {
+// VerifyReservationPasscode checks a passcode against the stored bcrypt
+// hashes. The reservation passcode and every B2B performer's own passcode
+// are accepted.
+func (db *DB) VerifyReservationPasscode(id uuid.UUID, passcode string) error {
 	var storedPasscode string
 	err := db.Get(&storedPasscode, "SELECT passcode FROM reservations WHERE id = $1", id)
 	if err != nil {
@@ -65,25 +137,47 @@ func (db *DB) DeleteReservation(id uuid.UUID, passcode string) error {
 		return fmt.Errorf("failed to get reservation: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(storedPasscode), []byte(passcode)); err != nil {
-		return fmt.Errorf("invalid passcode")
+	if bcrypt.CompareHashAndPassword([]byte(storedPasscode), []byte(passcode)) == nil {
+		return nil
 	}
 
-	result, err := db.Exec("DELETE FROM reservations WHERE id = $1", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete reservation: %w", err)
+	var performerPasscodes []string
+	query := `SELECT passcode FROM reservation_performers WHERE reservation_id = $1 AND passcode IS NOT NULL`
+	if err := db.Select(&performerPasscodes, query, id); err != nil {
+		return fmt.Errorf("failed to get performers: %w", err)
+	}
+	for _, hash := range performerPasscodes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(passcode)) == nil {
+			return nil
+		}
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	return fmt.Errorf("invalid passcode")
+}
+
+// DeleteReservation removes a reservation and returns it so that callers
+// know which time range was freed
+func (db *DB) DeleteReservation(id uuid.UUID, passcode string) (*Reservation, error) {
+	if err := db.VerifyReservationPasscode(id, passcode); err != nil {
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("reservation not found")
+	var reservation Reservation
+	query := `
+		DELETE FROM reservations
+		WHERE id = $1
+		RETURNING id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at
+	`
+
+	err := db.Get(&reservation, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reservation not found")
+		}
+		return nil, fmt.Errorf("failed to delete reservation: %w", err)
 	}
 
-	return nil
+	return &reservation, nil
 }
 
 func (db *DB) GetCurrentNextDJ() (*CurrentNextDJ, error) {
@@ -143,6 +237,11 @@ func (db *DB) GetAvailableSlotsInRange(startTime, endTime time.Time) ([]TimeSlot
 		return nil, err
 	}
 
+	holds, err := db.GetActiveSlotHoldsInRange(startTime.Add(-1*time.Hour), endTime.Add(1*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
 	slots := []TimeSlot{}
 
 	// Round startTime down to the nearest 15-minute interval
@@ -174,10 +273,23 @@ func (db *DB) GetAvailableSlotsInRange(startTime, endTime time.Time) ([]TimeSlot
 			}
 		}
 
+		// Slots held by someone filling in the booking form are unavailable too
+		held := false
+		if available {
+			for _, hold := range holds {
+				if currentTime.Before(hold.EndTime) && slotEnd.After(hold.StartTime) {
+					available = false
+					held = true
+					break
+				}
+			}
+		}
+
 		slots = append(slots, TimeSlot{
 			StartTime: currentTime,
 			EndTime:   slotEnd,
 			Available: available,
+			Held:      held,
 		})
 
 		currentTime = slotEnd
@@ -190,7 +302,7 @@ func (db *DB) GetReservationsInRange(startTime, endTime time.Time) ([]Reservatio
 	var reservations []Reservation
 
 	query := `
-		SELECT id, dj_name, start_time, end_time, passcode, created_at
+		SELECT id, dj_name, start_time, end_time, passcode, consent_record, consent_restream, consent_public_vod, created_at
 		FROM reservations
 		WHERE start_time < $2 AND end_time > $1
 		ORDER BY start_time
@@ -208,4 +320,119 @@ type TimeSlot struct {
 	StartTime time.Time
 	EndTime   time.Time
 	Available bool
+	// Held marks slots that are unavailable only because of an active hold
+	Held bool
+}
+
+// ClaimEndedReservations marks reservations that ended in (after, until] as
+// handled and returns them. Each reservation is returned once, even with
+// several replicas claiming concurrently.
+func (db *DB) ClaimEndedReservations(after, until time.Time) ([]Reservation, error) {
+	reservations := []Reservation{}
+
+	query := `
+		UPDATE reservations SET end_handled_at = CURRENT_TIMESTAMP
+		WHERE end_handled_at IS NULL AND end_time > $1 AND end_time <= $2
+		RETURNING id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at
+	`
+
+	if err := db.Select(&reservations, query, after, until); err != nil {
+		return nil, fmt.Errorf("failed to claim ended reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// GetNextReservation returns the first reservation starting at or after t,
+// or nil if there is none
+func (db *DB) GetNextReservation(t time.Time) (*Reservation, error) {
+	var reservation Reservation
+
+	query := `
+		SELECT id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at
+		FROM reservations
+		WHERE start_time >= $1
+		ORDER BY start_time
+		LIMIT 1
+	`
+
+	if err := db.Get(&reservation, query, t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get next reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// GetReservationAt returns the reservation on air at t, or nil if there is
+// none
+func (db *DB) GetReservationAt(t time.Time) (*Reservation, error) {
+	var reservation Reservation
+
+	query := `
+		SELECT id, dj_name, start_time, end_time, passcode, contact_email, consent_record, consent_restream, consent_public_vod, created_at
+		FROM reservations
+		WHERE start_time <= $1 AND end_time > $1
+	`
+
+	if err := db.Get(&reservation, query, t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// UpdateReservationConsent replaces what the DJs allow for their set.
+// Withdrawing consent to recording deletes the set's recordings and stream
+// sessions and returns the segment files for the caller to remove.
+// Withdrawing consent to public VOD hides the sessions already recorded;
+// granting it leaves their visibility as it is.
+func (db *DB) UpdateReservationConsent(id uuid.UUID, consent Consent) ([]string, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		UPDATE reservations
+		SET consent_record = $2, consent_restream = $3, consent_public_vod = $4
+		WHERE id = $1
+	`
+	result, err := tx.Exec(query, id, consent.Record, consent.Restream, consent.PublicVOD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update consent: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update consent: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("reservation not found")
+	}
+
+	files := []string{}
+	if !consent.Record {
+		if err := tx.Select(&files, "DELETE FROM recordings WHERE reservation_id = $1 RETURNING file", id); err != nil {
+			return nil, fmt.Errorf("failed to delete recordings: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM stream_sessions WHERE reservation_id = $1", id); err != nil {
+			return nil, fmt.Errorf("failed to delete stream sessions: %w", err)
+		}
+	} else if !consent.PublicVOD {
+		if _, err := tx.Exec("UPDATE stream_sessions SET vod_public = FALSE WHERE reservation_id = $1", id); err != nil {
+			return nil, fmt.Errorf("failed to hide stream sessions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return files, nil
 }
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Restream target statuses
+const (
+	RestreamStopped    = "stopped"
+	RestreamIdle       = "idle"
+	RestreamRunning    = "running"
+	RestreamRestarting = "restarting"
+)
+
+const restreamTargetColumns = `id, name, url, enabled, status, restart_count, last_error, owner, heartbeat_at, created_at, updated_at`
+
+// CreateRestreamTarget adds a target, stopped unless enabled
+func (db *DB) CreateRestreamTarget(name, url string, enabled bool) (*RestreamTarget, error) {
+	var target RestreamTarget
+	query := `
+		INSERT INTO restream_targets (id, name, url, enabled)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + restreamTargetColumns
+	if err := db.Get(&target, query, uuid.New(), name, url, enabled); err != nil {
+		return nil, fmt.Errorf("failed to create restream target: %w", err)
+	}
+	return &target, nil
+}
+
+// GetRestreamTargets returns all targets, oldest first
+func (db *DB) GetRestreamTargets() ([]RestreamTarget, error) {
+	targets := []RestreamTarget{}
+	query := `SELECT ` + restreamTargetColumns + ` FROM restream_targets ORDER BY created_at, id`
+	if err := db.Select(&targets, query); err != nil {
+		return nil, fmt.Errorf("failed to get restream targets: %w", err)
+	}
+	return targets, nil
+}
+
+// SetRestreamTargetEnabled starts or stops a target. Starting resets the
+// restart count and the last error.
+func (db *DB) SetRestreamTargetEnabled(id uuid.UUID, enabled bool) (*RestreamTarget, error) {
+	var target RestreamTarget
+	query := `
+		UPDATE restream_targets
+		SET enabled = $2,
+			restart_count = CASE WHEN $2 AND NOT enabled THEN 0 ELSE restart_count END,
+			last_error = CASE WHEN $2 AND NOT enabled THEN NULL ELSE last_error END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + restreamTargetColumns
+	if err := db.Get(&target, query, id, enabled); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("restream target not found")
+		}
+		return nil, fmt.Errorf("failed to update restream target: %w", err)
+	}
+	return &target, nil
+}
+
+// DeleteRestreamTarget removes a target; its forwarder stops on the next
+// reconciliation
+func (db *DB) DeleteRestreamTarget(id uuid.UUID) error {
+	result, err := db.Exec(`DELETE FROM restream_targets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete restream target: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("restream target not found")
+	}
+	return nil
+}
+
+// ClaimRestreamTarget makes owner responsible for forwarding to a target
+// unless another replica renewed its claim after staleBefore. It returns
+// false when the target belongs to another replica, is disabled or is gone.
+func (db *DB) ClaimRestreamTarget(id uuid.UUID, owner string, now, staleBefore time.Time) (bool, error) {
+	query := `
+		UPDATE restream_targets
+		SET owner = $2, heartbeat_at = $3
+		WHERE id = $1 AND enabled
+		AND (owner IS NULL OR owner = $2 OR heartbeat_at IS NULL OR heartbeat_at < $4)
+	`
+	result, err := db.Exec(query, id, owner, now, staleBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim restream target: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim restream target: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ReleaseRestreamTarget gives up a claim so that any replica can take over
+func (db *DB) ReleaseRestreamTarget(id uuid.UUID, owner string) error {
+	query := `UPDATE restream_targets SET owner = NULL, heartbeat_at = NULL WHERE id = $1 AND owner = $2`
+	if _, err := db.Exec(query, id, owner); err != nil {
+		return fmt.Errorf("failed to release restream target: %w", err)
+	}
+	return nil
+}
+
+// UpdateRestreamStatus records the forwarder state of a target. A restart
+// increments the restart count; lastError is kept when nil.
+func (db *DB) UpdateRestreamStatus(id uuid.UUID, status string, restarted bool, lastError *string) error {
+	query := `
+		UPDATE restream_targets
+		SET status = $2,
+			restart_count = restart_count + CASE WHEN $3::boolean THEN 1 ELSE 0 END,
+			last_error = COALESCE($4, last_error),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND (status <> $2 OR $3::boolean OR $4::text IS NOT NULL)
+	`
+	if _, err := db.Exec(query, id, status, restarted, lastError); err != nil {
+		return fmt.Errorf("failed to update restream status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AddTrack appends a track to a reservation's tracklist
+func (db *DB) AddTrack(reservationID uuid.UUID, artist, title string, playedAt time.Time) (*Track, error) {
+	track := &Track{
+		ID:            uuid.New(),
+		ReservationID: reservationID,
+		Artist:        artist,
+		Title:         title,
+		PlayedAt:      playedAt,
+	}
+
+	query := `
+		INSERT INTO tracks (id, reservation_id, artist, title, played_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	if err := db.QueryRow(query, track.ID, reservationID, artist, title, playedAt).Scan(&track.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to add track: %w", err)
+	}
+
+	return track, nil
+}
+
+// GetTracklist returns a reservation's tracks in the order they were played
+func (db *DB) GetTracklist(reservationID uuid.UUID) ([]Track, error) {
+	tracks := []Track{}
+
+	query := `
+		SELECT id, reservation_id, artist, title, played_at, created_at
+		FROM tracks
+		WHERE reservation_id = $1
+		ORDER BY played_at, created_at
+	`
+	if err := db.Select(&tracks, query, reservationID); err != nil {
+		return nil, fmt.Errorf("failed to get tracklist: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// GetCurrentTrack returns the last track of a reservation played at or
+// before now, or nil if there is none
+func (db *DB) GetCurrentTrack(reservationID uuid.UUID, now time.Time) (*Track, error) {
+	var track Track
+
+	query := `
+		SELECT id, reservation_id, artist, title, played_at, created_at
+		FROM tracks
+		WHERE reservation_id = $1 AND played_at <= $2
+		ORDER BY played_at DESC, created_at DESC
+		LIMIT 1
+	`
+	if err := db.Get(&track, query, reservationID, now); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get current track: %w", err)
+	}
+
+	return &track, nil
+}
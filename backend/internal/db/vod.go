@@ -0,0 +1,90 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxVODs caps the size of one VOD listing
+const maxVODs = 200
+
+const vodColumns = `s.id, s.reservation_id, r.dj_name, s.rtmp_key, s.started_at, s.ended_at, s.vod_public`
+
+// VODFilter narrows a VOD listing. Zero fields do not filter.
+type VODFilter struct {
+	// DJName matches part of the reservation's display name, ignoring case
+	DJName    string
+	ProfileID *uuid.UUID
+	From      *time.Time
+	To        *time.Time
+	// IncludeHidden also lists sessions that are not public
+	IncludeHidden bool
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// GetVODs returns finished sessions of reservations, newest first
+func (db *DB) GetVODs(filter VODFilter) ([]VOD, error) {
+	vods := []VOD{}
+
+	var djPattern string
+	if filter.DJName != "" {
+		djPattern = "%" + likeEscaper.Replace(filter.DJName) + "%"
+	}
+
+	query := `
+		SELECT ` + vodColumns + `
+		FROM stream_sessions s
+		JOIN reservations r ON r.id = s.reservation_id
+		WHERE s.ended_at IS NOT NULL
+		AND ($1 = '' OR r.dj_name ILIKE $1)
+		AND ($2::uuid IS NULL OR EXISTS (
+			SELECT 1 FROM reservation_performers p WHERE p.reservation_id = r.id AND p.profile_id = $2
+		))
+		AND ($3::timestamptz IS NULL OR s.started_at >= $3)
+		AND ($4::timestamptz IS NULL OR s.started_at < $4)
+		AND ($5 OR s.vod_public)
+		ORDER BY s.started_at DESC
+		LIMIT $6
+	`
+	err := db.Select(&vods, query, djPattern, filter.ProfileID, filter.From, filter.To, filter.IncludeHidden, maxVODs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vods: %w", err)
+	}
+
+	return vods, nil
+}
+
+// GetVOD returns a finished session of a reservation
+func (db *DB) GetVOD(id uuid.UUID) (*VOD, error) {
+	var vod VOD
+	query := `
+		SELECT ` + vodColumns + `
+		FROM stream_sessions s
+		JOIN reservations r ON r.id = s.reservation_id
+		WHERE s.id = $1 AND s.ended_at IS NOT NULL
+	`
+	if err := db.Get(&vod, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vod not found")
+		}
+		return nil, fmt.Errorf("failed to get vod: %w", err)
+	}
+	return &vod, nil
+}
+
+// SetVODPublic changes whether a session is listed publicly
+func (db *DB) SetVODPublic(id uuid.UUID, public bool) error {
+	result, err := db.Exec("UPDATE stream_sessions SET vod_public = $2 WHERE id = $1", id, public)
+	if err != nil {
+		return fmt.Errorf("failed to update vod: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("vod not found")
+	}
+	return nil
+}
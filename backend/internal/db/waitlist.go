@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Waitlist entry states
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistClaimed   = "claimed"
+	WaitlistExpired   = "expired"
+	WaitlistCancelled = "cancelled"
+)
+
+const waitlistColumns = `id, dj_name, contact_email, webhook_url, token_hash, start_time, end_time,
+	status, offered_at, offer_expires_at, created_at`
+
+// CreateWaitlistEntry adds a DJ to the waitlist and returns the entry with
+// its position among the waiting entries that overlap the same time
+func (db *DB) CreateWaitlistEntry(djName string, startTime, endTime time.Time, contactEmail, webhookURL *string, tokenHash string) (*WaitlistEntry, int, error) {
+	entry := WaitlistEntry{
+		ID:           uuid.New(),
+		DJName:       djName,
+		ContactEmail: contactEmail,
+		WebhookURL:   webhookURL,
+		TokenHash:    tokenHash,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Status:       WaitlistWaiting,
+		CreatedAt:    time.Now(),
+	}
+
+	query := `
+		INSERT INTO waitlist_entries (id, dj_name, contact_email, webhook_url, token_hash, start_time, end_time, status, created_at)
+		VALUES (:id, :dj_name, :contact_email, :webhook_url, :token_hash, :start_time, :end_time, :status, :created_at)
+	`
+	if _, err := db.NamedExec(query, entry); err != nil {
+		return nil, 0, fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	var position int
+	query = `
+		SELECT COUNT(*) FROM waitlist_entries
+		WHERE status = $1 AND start_time < $3 AND end_time > $2 AND created_at <= $4
+	`
+	if err := db.Get(&position, query, WaitlistWaiting, startTime, endTime, entry.CreatedAt); err != nil {
+		return nil, 0, fmt.Errorf("failed to get waitlist position: %w", err)
+	}
+
+	return &entry, position, nil
+}
+
+// CancelWaitlistEntry removes a DJ from the waitlist. An outstanding offer
+// is withdrawn together with its hold, which is returned so that the freed
+// range can be offered to the next entry.
+func (db *DB) CancelWaitlistEntry(id uuid.UUID, tokenHash string) (*SlotHold, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockSlots(tx); err != nil {
+		return nil, err
+	}
+
+	var entry WaitlistEntry
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries WHERE id = $1 FOR UPDATE`
+	if err := tx.Get(&entry, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("waitlist entry not found")
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	if entry.TokenHash != tokenHash {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if entry.Status != WaitlistWaiting && entry.Status != WaitlistOffered {
+		return nil, fmt.Errorf("waitlist entry not found")
+	}
+
+	if _, err := tx.Exec("UPDATE waitlist_entries SET status = $1 WHERE id = $2", WaitlistCancelled, id); err != nil {
+		return nil, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+
+	var hold *SlotHold
+	if entry.Status == WaitlistOffered {
+		var released SlotHold
+		query = `
+			DELETE FROM slot_holds WHERE token_hash = $1
+			RETURNING id, token_hash, start_time, end_time, expires_at, created_at, client_ip
+		`
+		err := tx.Get(&released, query, tokenHash)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to release slot hold: %w", err)
+		}
+		if err == nil {
+			hold = &released
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return hold, nil
+}
+
+// OfferWaitlistSlot finds the oldest waiting entry that overlaps a freed
+// range and whose whole range is now free, and gives it an exclusive hold
+// until claimBy. The hold's token is the entry's own token. It returns nil
+// if no entry can be offered the time.
+func (db *DB) OfferWaitlistSlot(startTime, endTime, claimBy time.Time) (*WaitlistEntry, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockSlots(tx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var entry WaitlistEntry
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries w
+		WHERE w.status = $1 AND w.start_time < $3 AND w.end_time > $2 AND w.start_time > $4
+		AND NOT EXISTS (
+			SELECT 1 FROM reservations r WHERE r.start_time < w.end_time AND r.end_time > w.start_time
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM slot_holds h WHERE h.start_time < w.end_time AND h.end_time > w.start_time AND h.expires_at > $4
+		)
+		ORDER BY w.created_at
+		LIMIT 1
+		FOR UPDATE
+	`
+	if err := tx.Get(&entry, query, WaitlistWaiting, startTime, endTime, now); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+
+	query = `
+		INSERT INTO slot_holds (id, token_hash, start_time, end_time, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(query, uuid.New(), entry.TokenHash, entry.StartTime, entry.EndTime, claimBy, now); err != nil {
+		return nil, fmt.Errorf("failed to hold slot for waitlist entry: %w", err)
+	}
+
+	query = `UPDATE waitlist_entries SET status = $1, offered_at = $2, offer_expires_at = $3 WHERE id = $4`
+	if _, err := tx.Exec(query, WaitlistOffered, now, claimBy, entry.ID); err != nil {
+		return nil, fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	entry.Status = WaitlistOffered
+	entry.OfferedAt = &now
+	entry.OfferExpiresAt = &claimBy
+	return &entry, nil
+}
+
+// ExpireWaitlistOffers marks offers that were not claimed in time. Their
+// holds expire at the same moment and are cleaned up with other holds.
+func (db *DB) ExpireWaitlistOffers(now time.Time) error {
+	query := `UPDATE waitlist_entries SET status = $1 WHERE status = $2 AND offer_expires_at <= $3`
+	if _, err := db.Exec(query, WaitlistExpired, WaitlistOffered, now); err != nil {
+		return fmt.Errorf("failed to expire waitlist offers: %w", err)
+	}
+	return nil
+}
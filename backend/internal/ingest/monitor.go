@@ -0,0 +1,326 @@
+// Package ingest watches the health of the stream publisher. It polls the
+// MediaMTX API for the stream path and its publishing connection, keeps a
+// rolling history and raises warnings when the bitrate collapses or the
+// publisher keeps reconnecting.
+package ingest
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/mediamtx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// baselineWindow is the period whose average bitrate a new sample is
+	// compared with
+	baselineWindow = time.Minute
+	// minBaselineSamples avoids warnings right after the publisher started
+	minBaselineSamples = 3
+	// minBaselineKbps avoids warnings for streams that were nearly silent
+	minBaselineKbps = 64
+)
+
+// Warning kinds
+const (
+	WarningBitrateDrop = "bitrate_drop"
+	WarningReconnects  = "reconnects"
+)
+
+type Config struct {
+	PathName     string
+	PollInterval time.Duration
+	History      time.Duration
+	// BitrateDropPercent of the previous minute's average bitrate below
+	// which a warning is raised (0 = no bitrate warnings)
+	BitrateDropPercent int
+	ReconnectLimit     int
+	ReconnectWindow    time.Duration
+	// OnWarning is called from the monitor goroutine
+	OnWarning func(Warning)
+}
+
+// Sample is one poll of the stream path
+type Sample struct {
+	At   time.Time
+	Live bool
+	// BitrateKbps is nil for the first poll of a publisher
+	BitrateKbps *float64
+	// FramesInError and PacketsLost count the errors since the previous poll
+	FramesInError uint64
+	PacketsLost   uint64
+}
+
+// Health is the latest state of the publisher
+type Health struct {
+	Live bool
+	// PublisherType is the MediaMTX source type, e.g. rtmpConn
+	PublisherType  string
+	PublisherSince *time.Time
+	BitrateKbps    *float64
+	VideoCodec     string
+	AudioCodec     string
+	Width          int
+	Height         int
+	// FramesInError and PacketsLost count the errors since the publisher started
+	FramesInError uint64
+	PacketsLost   uint64
+	// Connections counts publisher connections within the reconnect window
+	Connections int
+	// UpdatedAt is the last successful poll (zero before the first one)
+	UpdatedAt time.Time
+	History   []Sample
+}
+
+// Warning is raised once per incident, i.e. again only after the stream
+// recovered in between
+type Warning struct {
+	Kind string
+	At   time.Time
+	// BitrateKbps and BaselineKbps are set for bitrate_drop
+	BitrateKbps  float64
+	BaselineKbps float64
+	// Connections and Window are set for reconnects
+	Connections int
+	Window      time.Duration
+}
+
+type Monitor struct {
+	client *mediamtx.Client
+	config Config
+	logger *logrus.Logger
+
+	mu      sync.RWMutex
+	health  Health
+	samples []Sample
+	// starts holds when publishers connected within the reconnect window
+	starts []time.Time
+
+	// State of the current publisher
+	sourceID        string
+	readyTime       time.Time
+	lastBytes       uint64
+	lastFrames      uint64
+	lastLost        uint64
+	firstFrames     uint64
+	lastAt          time.Time
+	bitrateWarned   bool
+	reconnectWarned bool
+}
+
+func NewMonitor(client *mediamtx.Client, config Config, logger *logrus.Logger) *Monitor {
+	return &Monitor{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Run polls MediaMTX until the process exits
+func (m *Monitor) Run() {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	m.poll(time.Now())
+	for now := range ticker.C {
+		m.poll(now)
+	}
+}
+
+// Health returns a copy of the latest state including the history
+func (m *Monitor) Health() Health {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	health := m.health
+	health.History = append([]Sample(nil), m.samples...)
+	return health
+}
+
+func (m *Monitor) poll(now time.Time) {
+	path, err := m.client.GetPath(m.config.PathName)
+	if err != nil && !errors.Is(err, mediamtx.ErrNotFound) {
+		// Keep the last state; UpdatedAt shows that it is stale
+		m.logger.Debugf("Failed to get MediaMTX path: %v", err)
+		return
+	}
+
+	var warnings []Warning
+	m.mu.Lock()
+	if path == nil || !path.Ready || path.Source == nil {
+		m.recordOffline(now)
+	} else {
+		warnings = m.recordLive(path, now)
+	}
+	m.mu.Unlock()
+
+	if m.config.OnWarning != nil {
+		for _, warning := range warnings {
+			m.config.OnWarning(warning)
+		}
+	}
+}
+
+func (m *Monitor) recordOffline(now time.Time) {
+	m.sourceID = ""
+	m.lastAt = time.Time{}
+	m.bitrateWarned = false
+	m.trimStarts(now)
+
+	m.health = Health{
+		Connections: len(m.starts),
+		UpdatedAt:   now,
+	}
+	m.addSample(Sample{At: now})
+}
+
+// recordLive updates the state from a path with a publisher and returns
+// the warnings that became due
+func (m *Monitor) recordLive(path *mediamtx.Path, now time.Time) []Warning {
+	readyTime := now
+	if path.ReadyTime != nil {
+		readyTime = *path.ReadyTime
+	}
+
+	frames := path.InboundFramesInError
+	var lost uint64
+	if conn, err := m.client.GetConnection(*path.Source); err == nil {
+		lost = conn.PacketsLost()
+	} else {
+		m.logger.Debugf("Failed to get MediaMTX connection %s: %v", path.Source.ID, err)
+	}
+
+	// A new source ID or ready time means the publisher (re)connected
+	if path.Source.ID != m.sourceID || !readyTime.Equal(m.readyTime) {
+		m.sourceID = path.Source.ID
+		m.readyTime = readyTime
+		m.starts = append(m.starts, readyTime)
+		m.firstFrames = frames
+		m.lastFrames = frames
+		m.lastLost = 0
+		m.lastAt = time.Time{}
+		m.bitrateWarned = false
+	}
+	m.trimStarts(now)
+
+	sample := Sample{At: now, Live: true}
+	bytes := path.ReceivedBytes()
+	if !m.lastAt.IsZero() && bytes >= m.lastBytes && now.After(m.lastAt) {
+		kbps := float64(bytes-m.lastBytes) * 8 / now.Sub(m.lastAt).Seconds() / 1000
+		sample.BitrateKbps = &kbps
+	}
+	if frames >= m.lastFrames {
+		sample.FramesInError = frames - m.lastFrames
+	}
+	if lost >= m.lastLost {
+		sample.PacketsLost = lost - m.lastLost
+	}
+
+	var warnings []Warning
+	if warning, ok := m.checkBitrate(sample); ok {
+		warnings = append(warnings, warning)
+	}
+	if warning, ok := m.checkReconnects(now); ok {
+		warnings = append(warnings, warning)
+	}
+
+	m.lastBytes, m.lastFrames, m.lastLost, m.lastAt = bytes, frames, lost, now
+
+	since := m.readyTime
+	m.health = Health{
+		Live:           true,
+		PublisherType:  path.Source.Type,
+		PublisherSince: &since,
+		BitrateKbps:    sample.BitrateKbps,
+		PacketsLost:    lost,
+		Connections:    len(m.starts),
+		UpdatedAt:      now,
+	}
+	if frames >= m.firstFrames {
+		m.health.FramesInError = frames - m.firstFrames
+	}
+	m.health.VideoCodec, m.health.AudioCodec, m.health.Width, m.health.Height = describeTracks(path)
+
+	m.addSample(sample)
+	return warnings
+}
+
+// checkBitrate compares a sample with the average of the previous minute
+// of the same publisher
+func (m *Monitor) checkBitrate(sample Sample) (Warning, bool) {
+	if m.config.BitrateDropPercent <= 0 || sample.BitrateKbps == nil {
+		return Warning{}, false
+	}
+
+	var total float64
+	var count int
+	from := sample.At.Add(-baselineWindow)
+	for _, s := range m.samples {
+		if s.At.Before(from) || s.At.Before(m.readyTime) || !s.Live || s.BitrateKbps == nil {
+			continue
+		}
+		total += *s.BitrateKbps
+		count++
+	}
+	if count < minBaselineSamples {
+		return Warning{}, false
+	}
+
+	baseline := total / float64(count)
+	if baseline < minBaselineKbps {
+		return Warning{}, false
+	}
+	if *sample.BitrateKbps >= baseline*float64(m.config.BitrateDropPercent)/100 {
+		m.bitrateWarned = false
+		return Warning{}, false
+	}
+	if m.bitrateWarned {
+		return Warning{}, false
+	}
+
+	m.bitrateWarned = true
+	return Warning{
+		Kind:         WarningBitrateDrop,
+		At:           sample.At,
+		BitrateKbps:  *sample.BitrateKbps,
+		BaselineKbps: baseline,
+	}, true
+}
+
+func (m *Monitor) checkReconnects(now time.Time) (Warning, bool) {
+	if len(m.starts) < m.config.ReconnectLimit {
+		m.reconnectWarned = false
+		return Warning{}, false
+	}
+	if m.reconnectWarned {
+		return Warning{}, false
+	}
+
+	m.reconnectWarned = true
+	return Warning{
+		Kind:        WarningReconnects,
+		At:          now,
+		Connections: len(m.starts),
+		Window:      m.config.ReconnectWindow,
+	}, true
+}
+
+func (m *Monitor) trimStarts(now time.Time) {
+	from := now.Add(-m.config.ReconnectWindow)
+	i := 0
+	for i < len(m.starts) && m.starts[i].Before(from) {
+		i++
+	}
+	m.starts = m.starts[i:]
+}
+
+func (m *Monitor) addSample(sample Sample) {
+	from := sample.At.Add(-m.config.History)
+	i := 0
+	for i < len(m.samples) && m.samples[i].At.Before(from) {
+		i++
+	}
+	m.samples = append(m.samples[i:], sample)
+}
@@ -0,0 +1,51 @@
+package ingest
+
+import "github.com/dj-event/stream-system/internal/mediamtx"
+
+// Codec names as reported by MediaMTX
+var (
+	videoCodecs = map[string]bool{
+		"AV1": true, "VP9": true, "VP8": true, "H265": true, "H264": true,
+		"MPEG-4 Video": true, "MPEG-1/2 Video": true, "M-JPEG": true,
+	}
+	audioCodecs = map[string]bool{
+		"Opus": true, "Vorbis": true, "MPEG-4 Audio": true, "MPEG-4 Audio LATM": true,
+		"MPEG-1/2 Audio": true, "AC-3": true, "G711": true, "G722": true, "LPCM": true,
+	}
+)
+
+// describeTracks returns the first video and audio codec of a path and the
+// video resolution if MediaMTX reports it
+func describeTracks(path *mediamtx.Path) (video, audio string, width, height int) {
+	if len(path.Tracks2) == 0 {
+		for _, codec := range path.Tracks {
+			if video == "" && videoCodecs[codec] {
+				video = codec
+			}
+			if audio == "" && audioCodecs[codec] {
+				audio = codec
+			}
+		}
+		return video, audio, 0, 0
+	}
+
+	for _, track := range path.Tracks2 {
+		if video == "" && videoCodecs[track.Codec] {
+			video = track.Codec
+			width = intProp(track.CodecProps, "width")
+			height = intProp(track.CodecProps, "height")
+		}
+		if audio == "" && audioCodecs[track.Codec] {
+			audio = track.Codec
+		}
+	}
+	return video, audio, width, height
+}
+
+func intProp(props map[string]any, key string) int {
+	// JSON numbers decode as float64
+	if v, ok := props[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
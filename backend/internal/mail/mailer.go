@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type Mailer struct {
+	config Config
+	logger *logrus.Logger
+}
+
+func NewMailer(cfg Config, logger *logrus.Logger) *Mailer {
+	return &Mailer{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// Enabled reports whether an SMTP server has been configured
+func (m *Mailer) Enabled() bool {
+	return m.config.Host != ""
+}
+
+// Send delivers a plain-text message to a single recipient.
+// Authentication is skipped when no username is configured, which allows
+// local fake SMTP servers (e.g. mailpit) to be used during development.
+func (m *Mailer) Send(to, subject, body string) error {
+	if !m.Enabled() {
+		return fmt.Errorf("mailer is not configured")
+	}
+
+	addr := fmt.Sprintf("%s:%d", m.config.Host, m.config.Port)
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, m.buildMessage(to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	m.logger.Debugf("Sent mail to %s: %s", to, subject)
+	return nil
+}
+
+func (m *Mailer) buildMessage(to, subject, body string) []byte {
+	var b strings.Builder
+
+	headers := []string{
+		"From: " + m.config.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + uuid.New().String() + "@" + m.config.Host + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	for _, header := range headers {
+		b.WriteString(header)
+		b.WriteString("\r\n")
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,218 @@
+package mediamtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to the MediaMTX control API (api: yes, port 9997)
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 2 * time.Second,
+		},
+	}
+}
+
+// ErrNotFound is returned when the API does not know the requested item,
+// e.g. a path nobody is publishing to
+var ErrNotFound = errors.New("mediamtx: not found")
+
+type PathReader struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// PathSource is the publisher of a path, e.g. {"type": "rtmpConn", "id": "..."}
+type PathSource struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// PathTrack describes one published track. Codec properties depend on the
+// codec, e.g. width and height for video.
+type PathTrack struct {
+	Codec      string         `json:"codec"`
+	CodecProps map[string]any `json:"codecProps"`
+}
+
+type Path struct {
+	Name   string      `json:"name"`
+	Source *PathSource `json:"source"`
+	Ready  bool        `json:"ready"`
+	// ReadyTime is when the current publisher started
+	ReadyTime *time.Time `json:"readyTime"`
+	// Tracks lists codec names; Tracks2 has codec details on MediaMTX
+	// versions that provide it
+	Tracks  []string    `json:"tracks"`
+	Tracks2 []PathTrack `json:"tracks2"`
+	// InboundBytes replaced BytesReceived in newer MediaMTX versions
+	BytesReceived        uint64       `json:"bytesReceived"`
+	InboundBytes         uint64       `json:"inboundBytes"`
+	InboundFramesInError uint64       `json:"inboundFramesInError"`
+	Readers              []PathReader `json:"readers"`
+}
+
+// ReceivedBytes returns the bytes received from the publisher so far
+func (p *Path) ReceivedBytes() uint64 {
+	return max(p.InboundBytes, p.BytesReceived)
+}
+
+// Connection is a publishing or reading connection. Loss counters are only
+// reported by some protocols (RTSP, SRT).
+type Connection struct {
+	ID                       string    `json:"id"`
+	Created                  time.Time `json:"created"`
+	RemoteAddr               string    `json:"remoteAddr"`
+	State                    string    `json:"state"`
+	InboundRTPPacketsLost    uint64    `json:"inboundRTPPacketsLost"`
+	InboundRTPPacketsInError uint64    `json:"inboundRTPPacketsInError"`
+	PacketsReceivedDrop      uint64    `json:"packetsReceivedDrop"`
+	PacketsReceivedLoss      uint64    `json:"packetsReceivedLoss"`
+}
+
+// PacketsLost sums the loss counters of the connection
+func (c *Connection) PacketsLost() uint64 {
+	return c.InboundRTPPacketsLost + c.InboundRTPPacketsInError + c.PacketsReceivedDrop + c.PacketsReceivedLoss
+}
+
+// sourceCollections maps source types to their API collections
+var sourceCollections = map[string]string{
+	"rtmpConn":      "rtmpconns",
+	"rtmpsConn":     "rtmpsconns",
+	"rtspSession":   "rtspsessions",
+	"rtspsSession":  "rtspssessions",
+	"srtConn":       "srtconns",
+	"webRTCSession": "webrtcsessions",
+}
+
+// KickPath returns the API path that kicks a source
+func KickPath(source PathSource) (string, error) {
+	return sourcePath(source, "kick")
+}
+
+// ConnectionPath returns the API path that describes a source connection
+func ConnectionPath(source PathSource) (string, error) {
+	return sourcePath(source, "get")
+}
+
+func sourcePath(source PathSource, action string) (string, error) {
+	collection, ok := sourceCollections[source.Type]
+	if !ok {
+		return "", fmt.Errorf("mediamtx has no connections of type %q", source.Type)
+	}
+	return "/v3/" + collection + "/" + action + "/" + url.PathEscape(source.ID), nil
+}
+
+// GetPath returns the state of a single path
+func (c *Client) GetPath(name string) (*Path, error) {
+	var path Path
+	if err := c.get("/v3/paths/get/"+url.PathEscape(name), &path); err != nil {
+		return nil, err
+	}
+	return &path, nil
+}
+
+// CountExternalReaders returns the number of readers on a path that are not
+// HLS muxers. MediaMTX represents all HLS viewers of a path as a single
+// muxer reader, so only RTSP/RTMP/WebRTC/SRT players can be counted here.
+func (p *Path) CountExternalReaders() int {
+	count := 0
+	for _, reader := range p.Readers {
+		if reader.Type != "hlsMuxer" {
+			count++
+		}
+	}
+	return count
+}
+
+// GetConnection returns the connection of a path source
+func (c *Client) GetConnection(source PathSource) (*Connection, error) {
+	path, err := ConnectionPath(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn Connection
+	if err := c.get(path, &conn); err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
+
+// Kick disconnects the publisher of a path
+func (c *Client) Kick(source PathSource) error {
+	path, err := KickPath(source)
+	if err != nil {
+		return err
+	}
+
+	return c.send(http.MethodPost, path, nil)
+}
+
+// send makes a request without a response body
+func (c *Client) send(method, path string, body any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode mediamtx request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create mediamtx request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mediamtx request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return checkStatus(resp, path)
+}
+
+func (c *Client) get(path string, out any) error {
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("mediamtx request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp, path); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode mediamtx response: %w", err)
+	}
+	return nil
+}
+
+func checkStatus(resp *http.Response, path string) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, path)
+	default:
+		return fmt.Errorf("mediamtx returned status %d for %s", resp.StatusCode, path)
+	}
+}
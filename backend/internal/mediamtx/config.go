@@ -0,0 +1,41 @@
+package mediamtx
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// PathConf is the part of a path configuration that the backend manages.
+// Changes made through the API are lost when MediaMTX restarts, so callers
+// reapply them periodically.
+type PathConf struct {
+	Name string `json:"name,omitempty"`
+	// Fallback redirects RTSP readers to another path while this one has
+	// no publisher ("" = none)
+	Fallback *string `json:"fallback,omitempty"`
+	// RunOnInit starts a publisher together with the path
+	RunOnInit        *string `json:"runOnInit,omitempty"`
+	RunOnInitRestart *bool   `json:"runOnInitRestart,omitempty"`
+	// RunOnDemand starts a publisher when the first reader arrives
+	RunOnDemand        *string `json:"runOnDemand,omitempty"`
+	RunOnDemandRestart *bool   `json:"runOnDemandRestart,omitempty"`
+}
+
+// GetPathConf returns the configuration of a path
+func (c *Client) GetPathConf(name string) (*PathConf, error) {
+	var conf PathConf
+	if err := c.get("/v3/config/paths/get/"+url.PathEscape(name), &conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// AddPathConf adds a path configuration; other settings use the path defaults
+func (c *Client) AddPathConf(name string, conf PathConf) error {
+	return c.send(http.MethodPost, "/v3/config/paths/add/"+url.PathEscape(name), conf)
+}
+
+// PatchPathConf changes the set fields of a path configuration
+func (c *Client) PatchPathConf(name string, conf PathConf) error {
+	return c.send(http.MethodPatch, "/v3/config/paths/patch/"+url.PathEscape(name), conf)
+}
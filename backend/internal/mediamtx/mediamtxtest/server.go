@@ -0,0 +1,283 @@
+// Package mediamtxtest provides a stub of the MediaMTX control API for
+// tests. It serves the endpoints used by mediamtx.Client from in-memory
+// paths that the test sets up.
+//
+//	stub := mediamtxtest.NewServer()
+//	defer stub.Close()
+//	stub.Publish("stream-endpoint", "rtmpConn", "conn-1", time.Now())
+//	client := mediamtx.NewClient(stub.URL)
+package mediamtxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dj-event/stream-system/internal/mediamtx"
+)
+
+// Server is a running stub of the MediaMTX control API
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	paths  map[string]mediamtx.Path
+	confs  map[string]mediamtx.PathConf
+	kicked []mediamtx.PathSource
+	// connected holds the connection times of sources that took over a path
+	connected map[string]time.Time
+}
+
+// NewServer starts a stub without any paths. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		paths:     make(map[string]mediamtx.Path),
+		confs:     make(map[string]mediamtx.PathConf),
+		connected: make(map[string]time.Time),
+	}
+
+	mux := http.NewServeMux()
+	// Path names may contain slashes, so paths and connections share a route
+	mux.HandleFunc("GET /v3/{collection}/get/{name...}", s.get)
+	mux.HandleFunc("POST /v3/{collection}/kick/{id}", s.kick)
+	mux.HandleFunc("GET /v3/config/paths/get/{name...}", s.getPathConf)
+	mux.HandleFunc("POST /v3/config/paths/add/{name...}", s.addPathConf)
+	mux.HandleFunc("PATCH /v3/config/paths/patch/{name...}", s.patchPathConf)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// SetPath adds or replaces a path
+func (s *Server) SetPath(path mediamtx.Path) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths[path.Name] = path
+}
+
+// RemovePath removes a path, as if nobody published or read it any more
+func (s *Server) RemovePath(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.paths, name)
+}
+
+// Publish makes a source publish to a path since readyTime, keeping the
+// path's readers
+func (s *Server) Publish(name, sourceType, id string, readyTime time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.paths[name]
+	path.Name = name
+	path.Source = &mediamtx.PathSource{Type: sourceType, ID: id}
+	path.Ready = true
+	path.ReadyTime = &readyTime
+	s.paths[name] = path
+	delete(s.connected, id)
+}
+
+// TakeOver replaces the publisher of a path, as overridePublisher does,
+// with a source that connected at the given time. The path keeps its ready
+// time.
+func (s *Server) TakeOver(name, sourceType, id string, connected time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.paths[name]
+	path.Name = name
+	path.Source = &mediamtx.PathSource{Type: sourceType, ID: id}
+	if !path.Ready {
+		path.Ready = true
+		path.ReadyTime = &connected
+	}
+	s.paths[name] = path
+	s.connected[id] = connected
+}
+
+// Receive adds inbound traffic to a path, as the publisher sends media
+func (s *Server) Receive(name string, bytes, framesInError uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.paths[name]
+	path.Name = name
+	path.InboundBytes += bytes
+	path.InboundFramesInError += framesInError
+	s.paths[name] = path
+}
+
+// SetPathConf adds or replaces a path configuration, as if it was in
+// mediamtx.yml
+func (s *Server) SetPathConf(name string, conf mediamtx.PathConf) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conf.Name = name
+	s.confs[name] = conf
+}
+
+// PathConf returns a path configuration and whether it exists
+func (s *Server) PathConf(name string) (mediamtx.PathConf, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conf, ok := s.confs[name]
+	return conf, ok
+}
+
+// Kicked returns the sources kicked so far, oldest first
+func (s *Server) Kicked() []mediamtx.PathSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]mediamtx.PathSource(nil), s.kicked...)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("collection") == "paths" {
+		s.getPath(w, r)
+		return
+	}
+	s.getConnection(w, r)
+}
+
+func (s *Server) getPath(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	path, ok := s.paths[r.PathValue("name")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "path not found")
+		return
+	}
+	writeJSON(w, path)
+}
+
+// getConnection describes the source publishing to a path
+func (s *Server) getConnection(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range s.paths {
+		if path.Source == nil {
+			continue
+		}
+		connPath, err := mediamtx.ConnectionPath(*path.Source)
+		if err != nil || connPath != r.URL.EscapedPath() {
+			continue
+		}
+
+		conn := mediamtx.Connection{ID: path.Source.ID, State: "publish"}
+		if created, ok := s.connected[path.Source.ID]; ok {
+			conn.Created = created
+		} else if path.ReadyTime != nil {
+			conn.Created = *path.ReadyTime
+		}
+		writeJSON(w, conn)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, strings.TrimSuffix(r.PathValue("collection"), "s")+" not found")
+}
+
+// kick disconnects the source with the given ID from the path it publishes
+// to. The collection must match the source type, as in MediaMTX.
+func (s *Server) kick(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, path := range s.paths {
+		if path.Source == nil {
+			continue
+		}
+		kickPath, err := mediamtx.KickPath(*path.Source)
+		if err != nil || kickPath != r.URL.EscapedPath() {
+			continue
+		}
+
+		s.kicked = append(s.kicked, *path.Source)
+		path.Source = nil
+		path.Ready = false
+		path.ReadyTime = nil
+		s.paths[name] = path
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, strings.TrimSuffix(r.PathValue("collection"), "s")+" not found")
+}
+
+func (s *Server) getPathConf(w http.ResponseWriter, r *http.Request) {
+	conf, ok := s.PathConf(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "path configuration not found")
+		return
+	}
+	writeJSON(w, conf)
+}
+
+func (s *Server) addPathConf(w http.ResponseWriter, r *http.Request) {
+	var conf mediamtx.PathConf
+	if err := json.NewDecoder(r.Body).Decode(&conf); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := r.PathValue("name")
+	if _, ok := s.confs[name]; ok {
+		writeError(w, http.StatusBadRequest, "path already exists")
+		return
+	}
+	conf.Name = name
+	s.confs[name] = conf
+	w.WriteHeader(http.StatusOK)
+}
+
+// patchPathConf overwrites the fields present in the request
+func (s *Server) patchPathConf(w http.ResponseWriter, r *http.Request) {
+	var patch mediamtx.PathConf
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := r.PathValue("name")
+	conf, ok := s.confs[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "path configuration not found")
+		return
+	}
+	if patch.Fallback != nil {
+		conf.Fallback = patch.Fallback
+	}
+	if patch.RunOnInit != nil {
+		conf.RunOnInit = patch.RunOnInit
+	}
+	if patch.RunOnInitRestart != nil {
+		conf.RunOnInitRestart = patch.RunOnInitRestart
+	}
+	if patch.RunOnDemand != nil {
+		conf.RunOnDemand = patch.RunOnDemand
+	}
+	if patch.RunOnDemandRestart != nil {
+		conf.RunOnDemandRestart = patch.RunOnDemandRestart
+	}
+	s.confs[name] = conf
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
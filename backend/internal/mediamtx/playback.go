@@ -0,0 +1,20 @@
+package mediamtx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PlaybackURL returns the URL at which the MediaMTX playback server (playback:
+// yes, port 9996) serves a time range of a path's recordings as one
+// fragmented MP4 stream, which browsers play while it downloads
+func PlaybackURL(baseURL, pathName string, start time.Time, duration time.Duration) string {
+	query := url.Values{}
+	query.Set("path", pathName)
+	query.Set("start", start.UTC().Format(time.RFC3339Nano))
+	query.Set("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+	query.Set("format", "fmp4")
+	return strings.TrimRight(baseURL, "/") + "/get?" + query.Encode()
+}
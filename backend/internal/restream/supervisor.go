@@ -0,0 +1,252 @@
+// Package restream forwards the live stream to other RTMP servers. The
+// supervisor runs one ffmpeg process per target and restarts it with a
+// backoff when it exits, e.g. because the destination dropped the
+// connection.
+package restream
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	minBackoff = 2 * time.Second
+	maxBackoff = time.Minute
+	// stableRun resets the backoff when a process ran at least this long
+	stableRun = time.Minute
+	// startupWindow is how long a process must run before it counts as
+	// forwarding; ffmpeg exits within it when it cannot connect
+	startupWindow = 5 * time.Second
+	// maxErrorLength keeps the tail of the process output as the error
+	maxErrorLength = 500
+	// maxOutputTail bounds the process output kept in memory
+	maxOutputTail = 4096
+)
+
+// Event kinds. EventStarted and EventRestarted are reported once the
+// process survived the startup window.
+const (
+	EventStarted = "started"
+	// EventExited is followed by another attempt unless the target stopped
+	EventExited    = "exited"
+	EventRestarted = "restarted"
+	EventStopped   = "stopped"
+)
+
+// Target is a destination to forward to
+type Target struct {
+	ID  uuid.UUID
+	URL string
+}
+
+// Event reports a state change of a target's forwarder
+type Event struct {
+	TargetID uuid.UUID
+	Kind     string
+	// Err is set for EventExited
+	Err error
+}
+
+type Config struct {
+	// FFmpeg is the ffmpeg binary
+	FFmpeg string
+	// SourceURL is where the live stream is read from, e.g. the MediaMTX
+	// RTSP output
+	SourceURL string
+	// OnEvent is called from the forwarder goroutines
+	OnEvent func(Event)
+}
+
+type Supervisor struct {
+	config Config
+	logger *logrus.Logger
+
+	mu         sync.Mutex
+	forwarders map[uuid.UUID]*forwarder
+}
+
+type forwarder struct {
+	url    string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewSupervisor(config Config, logger *logrus.Logger) *Supervisor {
+	return &Supervisor{
+		config:     config,
+		logger:     logger,
+		forwarders: make(map[uuid.UUID]*forwarder),
+	}
+}
+
+// Reconcile runs a forwarder for exactly the given targets. Forwarders of
+// other targets, or of targets whose URL changed, are stopped.
+func (s *Supervisor) Reconcile(targets []Target) {
+	wanted := make(map[uuid.UUID]string, len(targets))
+	for _, target := range targets {
+		wanted[target.ID] = target.URL
+	}
+
+	s.mu.Lock()
+	var stopping []*forwarder
+	for id, fw := range s.forwarders {
+		if url, ok := wanted[id]; !ok || url != fw.url {
+			fw.cancel()
+			stopping = append(stopping, fw)
+			delete(s.forwarders, id)
+		}
+	}
+	for id, url := range wanted {
+		if _, ok := s.forwarders[id]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		fw := &forwarder{url: url, cancel: cancel, done: make(chan struct{})}
+		s.forwarders[id] = fw
+		go s.run(ctx, id, fw)
+	}
+	s.mu.Unlock()
+
+	// Wait outside the lock so that the stopped events are reported before
+	// the caller acts on the new state
+	for _, fw := range stopping {
+		<-fw.done
+	}
+}
+
+// Running returns the IDs of the targets with a forwarder
+func (s *Supervisor) Running() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(s.forwarders))
+	for id := range s.forwarders {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// run starts the forwarding process until the context is cancelled
+func (s *Supervisor) run(ctx context.Context, id uuid.UUID, fw *forwarder) {
+	defer close(fw.done)
+	defer s.emit(Event{TargetID: id, Kind: EventStopped})
+
+	backoff := minBackoff
+	for restart := false; ; restart = true {
+		kind := EventStarted
+		if restart {
+			kind = EventRestarted
+		}
+
+		started := time.Now()
+		err := s.forward(ctx, fw.url, func() { s.emit(Event{TargetID: id, Kind: kind}) })
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("ffmpeg exited")
+		}
+		s.logger.Warnf("Restream forwarder for target %s exited: %v", id, err)
+		s.emit(Event{TargetID: id, Kind: EventExited, Err: err})
+
+		if time.Since(started) >= stableRun {
+			backoff = minBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// forward runs ffmpeg, copying the source to the target without
+// re-encoding. onRunning is called once the process survived the startup
+// window.
+func (s *Supervisor) forward(ctx context.Context, url string, onRunning func()) error {
+	format := "flv"
+	if strings.HasPrefix(url, "srt://") {
+		format = "mpegts"
+	}
+
+	cmd := exec.CommandContext(ctx, s.config.FFmpeg,
+		"-nostdin", "-loglevel", "error",
+		"-rtsp_transport", "tcp", "-i", s.config.SourceURL,
+		"-c", "copy", "-f", format, url)
+	stderr := NewTailBuffer()
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-exited:
+	case <-time.After(startupWindow):
+		onRunning()
+		err = <-exited
+	}
+	if err != nil {
+		if output := LastLine(stderr.String()); output != "" {
+			return errors.New(output)
+		}
+		return err
+	}
+	return nil
+}
+
+// TailBuffer keeps the last bytes written to it, so that a process
+// logging for hours does not grow it without bounds
+type TailBuffer struct {
+	buf []byte
+	max int
+}
+
+func NewTailBuffer() *TailBuffer {
+	return &TailBuffer{max: maxOutputTail}
+}
+
+func (b *TailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= b.max {
+		b.buf = append(b.buf[:0], p[n-b.max:]...)
+		return n, nil
+	}
+	if drop := len(b.buf) + n - b.max; drop > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[drop:])]
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+func (b *TailBuffer) String() string {
+	return string(b.buf)
+}
+
+func (s *Supervisor) emit(event Event) {
+	if s.config.OnEvent != nil {
+		s.config.OnEvent(event)
+	}
+}
+
+// LastLine returns the last non-empty line of the process output, which
+// usually names the cause
+func LastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > maxErrorLength {
+		line = line[len(line)-maxErrorLength:]
+	}
+	return line
+}
@@ -0,0 +1,198 @@
+package websocket
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type ChatConfig struct {
+	// MaxLength is the maximum chat message length in characters
+	MaxLength int
+	// HistorySize is the number of recent messages sent to new clients
+	HistorySize int
+	// RateBurst and RateInterval define a per-client token bucket:
+	// up to RateBurst messages at once, refilled by one every RateInterval
+	RateBurst    int
+	RateInterval time.Duration
+	// OnMessage is called after a message has been accepted, e.g. to persist it
+	OnMessage func(ChatMessage)
+}
+
+const maxNicknameLength = 30
+
+type ChatMessage struct {
+	ID       uuid.UUID `json:"id"`
+	ViewerID string    `json:"-"`
+	IP       string    `json:"-"`
+	Nickname string    `json:"nickname"`
+	Body     string    `json:"body"`
+	SentAt   time.Time `json:"sentAt"`
+}
+
+// defaultNickname derives a stable nickname from the viewer identity so the
+// same person keeps their name across tabs and reconnects
+func defaultNickname(viewerID string) string {
+	sum := sha256.Sum256([]byte(viewerID))
+	return fmt.Sprintf("viewer-%04d", binary.BigEndian.Uint16(sum[:2])%10000)
+}
+
+// sanitizeChatText trims surrounding whitespace and rejects control characters
+// other than plain spaces, returning false if the text is unusable
+func sanitizeChatText(text string, maxLength int) (string, bool) {
+	text = strings.TrimSpace(text)
+	length := utf8.RuneCountInString(text)
+	if length == 0 || length > maxLength {
+		return "", false
+	}
+	if strings.IndexFunc(text, unicode.IsControl) >= 0 {
+		return "", false
+	}
+	return text, true
+}
+
+// rateLimiter is a token bucket owned by a single client's read loop
+type rateLimiter struct {
+	tokens   float64
+	last     time.Time
+	burst    int
+	interval time.Duration
+}
+
+func newRateLimiter(burst int, interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		tokens:   float64(burst),
+		last:     time.Now(),
+		burst:    burst,
+		interval: interval,
+	}
+}
+
+func (l *rateLimiter) Allow(now time.Time) bool {
+	if l.interval > 0 {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+func (c *Client) handleSetNickname(nickname string) {
+	nickname, ok := sanitizeChatText(nickname, maxNicknameLength)
+	if !ok {
+		c.sendChatError("INVALID_NICKNAME", fmt.Sprintf("Nickname must be 1-%d characters", maxNicknameLength))
+		return
+	}
+
+	c.mu.Lock()
+	c.nickname = nickname
+	c.mu.Unlock()
+
+	c.SendMessage(TypeNickname, NicknamePayload{Nickname: nickname})
+}
+
+func (c *Client) handleChat(body string) {
+	cfg := c.Manager.config.Chat
+
+	body, ok := sanitizeChatText(body, cfg.MaxLength)
+	if !ok {
+		c.sendChatError("INVALID_MESSAGE", fmt.Sprintf("Message must be 1-%d characters", cfg.MaxLength))
+		return
+	}
+
+	now := time.Now()
+	if !c.chatLimiter.Allow(now) {
+		c.sendChatError("RATE_LIMITED", "You are sending messages too quickly")
+		return
+	}
+
+	// Moderators are exempt from bans and chat modes
+	if c.moderatorName() == "" {
+		if code, message := c.Manager.checkChatAllowed(c, now); code != "" {
+			c.sendChatError(code, message)
+			return
+		}
+	}
+
+	msg := ChatMessage{
+		ID:       uuid.New(),
+		ViewerID: c.ViewerID,
+		IP:       c.IP,
+		Nickname: c.Nickname(),
+		Body:     c.Manager.filterBlockedWords(body),
+		SentAt:   now,
+	}
+
+	c.Manager.chat <- msg
+
+	if cfg.OnMessage != nil {
+		cfg.OnMessage(msg)
+	}
+}
+
+func (c *Client) Nickname() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nickname
+}
+
+func (c *Client) sendChatError(code, message string) {
+	c.SendMessage(TypeChatError, ChatErrorPayload{Code: code, Message: message})
+}
+
+// appendChatHistory stores a message in the bounded history buffer.
+// Only called from the run loop.
+func (m *Manager) appendChatHistory(msg ChatMessage) {
+	if m.config.Chat.HistorySize <= 0 {
+		return
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	if len(m.chatHistory) >= m.config.Chat.HistorySize {
+		copy(m.chatHistory, m.chatHistory[1:])
+		m.chatHistory = m.chatHistory[:len(m.chatHistory)-1]
+	}
+	m.chatHistory = append(m.chatHistory, msg)
+}
+
+// removeChatHistory drops a message from the history buffer, returning
+// false if it was not there
+func (m *Manager) removeChatHistory(id uuid.UUID) bool {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	for i, msg := range m.chatHistory {
+		if msg.ID == id {
+			m.chatHistory = append(m.chatHistory[:i], m.chatHistory[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) sendChatHistory(client *Client) {
+	m.historyMu.Lock()
+	messages := make([]ChatMessage, len(m.chatHistory))
+	copy(messages, m.chatHistory)
+	m.historyMu.Unlock()
+
+	client.SendMessage(TypeChatHistory, ChatHistoryPayload{Messages: messages})
+}
+
+func (m *Manager) sendChatSettings(client *Client) {
+	client.SendMessage(TypeChatSettings, newChatSettingsPayload(m.ChatSettings()))
+}
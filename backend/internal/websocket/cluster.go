@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cluster relays events between backend replicas so that every replica can
+// serve the same broadcasts and report the global viewer count.
+// Publish must not block; implementations queue or drop events.
+type Cluster interface {
+	Publish(event ClusterEvent)
+}
+
+// Kinds of events exchanged between replicas
+const (
+	ClusterCounts    = "counts"
+	ClusterMessage   = "message"
+	ClusterReactions = "reactions"
+	ClusterBan       = "ban"
+	ClusterUnban     = "unban"
+)
+
+const (
+	// clusterHeartbeat is how often a replica republishes its counts
+	clusterHeartbeat = 10 * time.Second
+	// clusterPeerTimeout drops the counts of replicas that stopped publishing
+	clusterPeerTimeout = 3 * clusterHeartbeat
+)
+
+// ClusterEvent is the unit of replication. Replica is filled in by the
+// Cluster implementation and used to ignore a replica's own events.
+type ClusterEvent struct {
+	Kind    string `json:"kind"`
+	Replica string `json:"replica,omitempty"`
+
+	Counts *ViewerCounts `json:"counts,omitempty"`
+
+	// Type and Payload carry a broadcast message for ClusterMessage.
+	// ViewerID and IP identify the author of chat messages, which the
+	// public payload leaves out, so that any replica can ban them.
+	Type     string          `json:"type,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	ViewerID string          `json:"viewerId,omitempty"`
+	IP       string          `json:"ip,omitempty"`
+
+	Reactions map[string]int `json:"reactions,omitempty"`
+	Ban       *Ban           `json:"ban,omitempty"`
+	BanID     *uuid.UUID     `json:"banId,omitempty"`
+}
+
+// peerCounts is the last count reported by another replica
+type peerCounts struct {
+	counts ViewerCounts
+	seenAt time.Time
+}
+
+// HandleClusterEvent feeds an event received from another replica into the run loop
+func (m *Manager) HandleClusterEvent(event ClusterEvent) {
+	m.remote <- event
+}
+
+func (m *Manager) publish(event ClusterEvent) {
+	if m.config.Cluster != nil {
+		m.config.Cluster.Publish(event)
+	}
+}
+
+// publishMessage replicates a locally originated broadcast. Only called from the run loop.
+func (m *Manager) publishMessage(msgType string, payload any) {
+	if m.config.Cluster == nil {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		m.logger.Errorf("Failed to encode %s for other replicas: %v", msgType, err)
+		return
+	}
+
+	event := ClusterEvent{Kind: ClusterMessage, Type: msgType, Payload: data}
+	if msg, ok := payload.(ChatMessage); ok {
+		event.ViewerID = msg.ViewerID
+		event.IP = msg.IP
+	}
+	m.config.Cluster.Publish(event)
+}
+
+// publishCounts shares this replica's audience with the others. Only called from the run loop.
+func (m *Manager) publishCounts() {
+	if m.config.Cluster == nil {
+		return
+	}
+
+	counts := m.localViewerCounts()
+	m.config.Cluster.Publish(ClusterEvent{Kind: ClusterCounts, Counts: &counts})
+}
+
+// applyClusterEvent applies another replica's event locally. Only called from the run loop.
+func (m *Manager) applyClusterEvent(event ClusterEvent, now time.Time) {
+	switch event.Kind {
+	case ClusterCounts:
+		if event.Counts == nil {
+			return
+		}
+		m.mu.Lock()
+		m.peers[event.Replica] = peerCounts{counts: *event.Counts, seenAt: now}
+		m.mu.Unlock()
+		m.scheduleViewerCount()
+
+	case ClusterMessage:
+		m.applyRemoteMessage(event)
+		m.broadcastMessage(event.Type, event.Payload)
+
+	case ClusterReactions:
+		for name, count := range event.Reactions {
+			m.reactionAgg.remote[name] += count
+		}
+
+	case ClusterBan:
+		if event.Ban != nil {
+			m.moderation.mu.Lock()
+			m.moderation.bans[event.Ban.ID] = *event.Ban
+			m.moderation.mu.Unlock()
+		}
+
+	case ClusterUnban:
+		if event.BanID != nil {
+			m.moderation.mu.Lock()
+			delete(m.moderation.bans, *event.BanID)
+			m.moderation.mu.Unlock()
+		}
+	}
+}
+
+// applyRemoteMessage keeps local state in sync with broadcasts made by other
+// replicas, e.g. so that new clients get their chat messages in the history
+func (m *Manager) applyRemoteMessage(event ClusterEvent) {
+	switch event.Type {
+	case TypeChat:
+		var msg ChatMessage
+		if err := json.Unmarshal(event.Payload, &msg); err == nil {
+			msg.ViewerID = event.ViewerID
+			msg.IP = event.IP
+			m.appendChatHistory(msg)
+		}
+
+	case TypeChatDelete:
+		var p ChatDeletePayload
+		if err := json.Unmarshal(event.Payload, &p); err == nil {
+			m.removeChatHistory(p.ID)
+		}
+
+	case TypeChatSettings:
+		var p ChatSettingsPayload
+		if err := json.Unmarshal(event.Payload, &p); err == nil {
+			m.moderation.mu.Lock()
+			m.moderation.setSettings(ChatSettings{
+				SlowMode:       time.Duration(p.SlowModeSeconds) * time.Second,
+				SubscriberOnly: p.SubscriberOnly,
+			})
+			m.moderation.mu.Unlock()
+		}
+	}
+}
+
+// expirePeers forgets replicas that stopped publishing, returning true if
+// the global count changed. Only called from the run loop.
+func (m *Manager) expirePeers(now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expired := false
+	for replica, peer := range m.peers {
+		if now.Sub(peer.seenAt) > clusterPeerTimeout {
+			delete(m.peers, replica)
+			expired = true
+		}
+	}
+	return expired
+}
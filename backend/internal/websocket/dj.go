@@ -0,0 +1,74 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message types of the DJ channel (/ws/dj). The channel uses the same
+// envelope as the viewer WebSocket; slot_ended and error are shared.
+// Admins authenticate with admin_auth and only receive ingest warnings.
+const (
+	TypeDJAuth         = "dj_auth"
+	TypeAdminAuth      = "admin_auth"
+	TypeHandoverStatus = "handover_status"
+	TypeTimeWarning    = "time_warning"
+	TypeNextDJReady    = "next_dj_ready"
+	TypeIngestWarning  = "ingest_warning"
+)
+
+// Error codes of the DJ channel, in addition to the viewer ones
+const (
+	ErrorAuthRequired    = "AUTH_REQUIRED"
+	ErrorInvalidPasscode = "INVALID_PASSCODE"
+	ErrorInvalidToken    = "INVALID_TOKEN"
+)
+
+// DJAuthPayload authenticates a DJ channel connection for one reservation
+type DJAuthPayload struct {
+	ReservationID *uuid.UUID `json:"reservationId"`
+	Passcode      string     `json:"passcode"`
+}
+
+// AdminAuthPayload authenticates a DJ channel connection as an admin
+type AdminAuthPayload struct {
+	Token string `json:"token"`
+}
+
+// TimeWarningPayload tells the DJ on air that their slot ends soon
+type TimeWarningPayload struct {
+	MinutesLeft int       `json:"minutesLeft"`
+	EndTime     time.Time `json:"endTime"`
+}
+
+// NextDJReadyPayload tells the DJ on air that the next DJ has their DJ
+// channel or overlay open
+type NextDJReadyPayload struct {
+	ReservationID uuid.UUID `json:"reservationId"`
+	DJName        string    `json:"djName"`
+	StartTime     time.Time `json:"startTime"`
+}
+
+// IngestWarningPayload tells the DJ on air and admins that the publisher's
+// stream is in trouble. Kind is bitrate_drop or reconnects.
+type IngestWarningPayload struct {
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+	// BitrateKbps and BaselineKbps are set for bitrate_drop
+	BitrateKbps  *float64 `json:"bitrateKbps,omitempty"`
+	BaselineKbps *float64 `json:"baselineKbps,omitempty"`
+	// Connections and WindowMinutes are set for reconnects
+	Connections   *int `json:"connections,omitempty"`
+	WindowMinutes *int `json:"windowMinutes,omitempty"`
+}
+
+// EncodeMessage encodes a message addressed to a single connection
+func EncodeMessage(msgType string, payload any) ([]byte, error) {
+	frame, err := newFrame(msgType, 0, payload, false)
+	if err != nil {
+		return nil, err
+	}
+	return frame.Data, nil
+}
@@ -1,10 +1,12 @@
 package websocket
 
 import (
+	"errors"
 	"net"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,13 +14,39 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// sendBufferSize is the number of frames a client may fall behind by
+	// before it is evicted as a slow consumer
+	sendBufferSize = 256
+	// defaultViewerCountDebounce bounds how often viewer counts are broadcast
+	defaultViewerCountDebounce = 500 * time.Millisecond
+)
+
 type Client struct {
 	ID       string
+	IP       string
+	ViewerID string
 	Conn     *websocket.Conn
 	Manager  *Manager
-	Send     chan []byte
+	Send     chan *Frame
 	lastPing time.Time
-	mu       sync.Mutex
+	playing  bool
+	nickname string
+	closed   bool
+	// evicted is set when the client was closed for falling behind
+	evicted bool
+	// moderator is the authenticated moderator name ("" for viewers)
+	moderator string
+	mu        sync.Mutex
+
+	// resumeAfter is the last broadcast seen by a reconnecting event stream
+	// client; resuming is false when there is nothing to resume
+	resumeAfter uint64
+	resuming    bool
+
+	// chatLimiter and reactionLimiter are only touched by ReadPump
+	chatLimiter     *rateLimiter
+	reactionLimiter *rateLimiter
 }
 
 func (c *Client) SetLastPing(t time.Time) {
@@ -33,23 +61,223 @@ func (c *Client) GetLastPing() time.Time {
 	return c.lastPing
 }
 
+// SetPlaying records whether the client's player is currently running.
+// It returns true if the state changed.
+func (c *Client) SetPlaying(playing bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := c.playing != playing
+	c.playing = playing
+	return changed
+}
+
+func (c *Client) IsPlaying() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.playing
+}
+
+// trySend queues a frame without blocking. It returns false if the client
+// has already been closed or could not keep up. A client whose buffer is
+// full is evicted: its send channel is closed so that its connection shuts
+// down, and the run loop drops it on the next send or on unregister.
+func (c *Client) trySend(frame *Frame) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Send <- frame:
+		return true
+	default:
+		c.closed = true
+		c.evicted = true
+		close(c.Send)
+		c.Manager.metrics.RecordRejection(RejectSlowConsumer)
+		c.Manager.logger.Warnf("Evicting client %s (%s): %d messages behind", c.ID, c.IP, cap(c.Send))
+		return false
+	}
+}
+
+// isEvicted reports whether the client was closed for falling behind
+func (c *Client) isEvicted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evicted
+}
+
+// closeSend closes the send channel once, which makes WritePump exit
+func (c *Client) closeSend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+// ViewerCounts describes the audience from different angles
+type ViewerCounts struct {
+	// Connections is the number of open WebSocket connections
+	Connections int
+	// UniqueViewers counts distinct viewer identities with a playing player
+	UniqueViewers int
+	// ExternalViewers are non-HLS readers reported by MediaMTX
+	ExternalViewers int
+}
+
+// Total is the headline viewer count shown to the audience
+func (v ViewerCounts) Total() int {
+	return v.UniqueViewers + v.ExternalViewers
+}
+
+type Config struct {
+	// MaxConnectionsPerIP caps concurrent connections from one address (0 = unlimited)
+	MaxConnectionsPerIP int
+	// MaxMessageSize is the read limit applied to inbound messages in bytes
+	MaxMessageSize int64
+	Chat           ChatConfig
+	Reactions      ReactionConfig
+	Moderation     ModerationConfig
+	// Cluster shares counts and broadcasts with other replicas (nil = single replica)
+	Cluster Cluster
+	// ViewerCountDebounce is the minimum interval between viewer count
+	// broadcasts; changes in between are coalesced (0 = default)
+	ViewerCountDebounce time.Duration
+}
+
 type Manager struct {
 	clients    map[string]*Client
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
 	logger     *logrus.Logger
+	config     Config
+	metrics    *Metrics
+
+	// external carries MediaMTX reader counts into the run loop and
+	// countChanged signals that a client's player state changed
+	external        chan int
+	externalViewers atomic.Int64
+	countChanged    chan struct{}
+
+	// countTimer is armed while a viewer count broadcast is pending and
+	// countDue is its channel (nil when idle). countPublish records that the
+	// local audience changed and must be published to other replicas.
+	// lastCount is the last broadcast payload. Only used from the run loop.
+	countTimer   *time.Timer
+	countDue     <-chan time.Time
+	countPublish bool
+	lastCount    ViewerCountPayload
+
+	// remote carries events from other replicas into the run loop;
+	// peers holds their latest counts and is guarded by mu
+	remote chan ClusterEvent
+	peers  map[string]peerCounts
+
+	broadcast chan outboundMessage
+	// broadcastSeq is the sequence number of the last broadcast message and
+	// recent holds the latest broadcasts for resumption. Only used from the
+	// run loop. bootID distinguishes sequence numbers of different processes.
+	broadcastSeq uint64
+	recent       []*Frame
+	bootID       string
+	// ping is shared by all keep-alive rounds and snapshot is a reusable
+	// buffer of clients. Only used from the run loop.
+	ping     *Frame
+	snapshot []*Client
+
+	chat        chan ChatMessage
+	historyMu   sync.Mutex
+	chatHistory []ChatMessage
+	moderation  *moderationState
+	reactions   chan string
+	reactionAgg *reactionAggregator
+
+	ipMu    sync.Mutex
+	ipConns map[string]int
 }
 
-func NewManager(logger *logrus.Logger) *Manager {
+func NewManager(cfg Config, logger *logrus.Logger) *Manager {
+	if cfg.ViewerCountDebounce <= 0 {
+		cfg.ViewerCountDebounce = defaultViewerCountDebounce
+	}
+
+	ping, err := newFrame(TypePing, 0, nil, true)
+	if err != nil {
+		// Encoding a fixed envelope cannot fail
+		panic(err)
+	}
+
 	return &Manager{
-		clients:    make(map[string]*Client),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		logger:     logger,
+		clients:      make(map[string]*Client),
+		register:     make(chan *Client),
+		unregister:   make(chan *Client),
+		logger:       logger,
+		config:       cfg,
+		metrics:      newMetrics(),
+		external:     make(chan int),
+		countChanged: make(chan struct{}, 1),
+		remote:       make(chan ClusterEvent, 256),
+		peers:        make(map[string]peerCounts),
+		broadcast:    make(chan outboundMessage, 64),
+		bootID:       strconv.FormatInt(time.Now().UnixMilli(), 36),
+		ping:         ping,
+		chat:         make(chan ChatMessage, 64),
+		reactions:    make(chan string, 1024),
+		reactionAgg:  newReactionAggregator(time.Now()),
+		moderation:   newModerationState(cfg.Moderation),
+		ipConns:      make(map[string]int),
+	}
+}
+
+// SetExternalViewers updates the number of viewers watching through
+// MediaMTX directly (RTSP, RTMP, WebRTC, ...) and notifies clients if it changed
+func (m *Manager) SetExternalViewers(count int) {
+	m.external <- count
+}
+
+func (m *Manager) notifyCountChanged() {
+	select {
+	case m.countChanged <- struct{}{}:
+	default:
+		// A broadcast is already pending
 	}
 }
 
+func (m *Manager) Metrics() *Metrics {
+	return m.metrics
+}
+
+// AcquireIP reserves a connection slot for the given address.
+// It returns false when the per-IP cap has been reached.
+// Every successful call must be paired with ReleaseIP.
+func (m *Manager) AcquireIP(ip string) bool {
+	m.ipMu.Lock()
+	defer m.ipMu.Unlock()
+
+	if m.config.MaxConnectionsPerIP > 0 && m.ipConns[ip] >= m.config.MaxConnectionsPerIP {
+		m.metrics.RecordRejection(RejectIPLimit)
+		return false
+	}
+	m.ipConns[ip]++
+	return true
+}
+
+func (m *Manager) ReleaseIP(ip string) {
+	m.ipMu.Lock()
+	defer m.ipMu.Unlock()
+
+	if m.ipConns[ip] <= 1 {
+		delete(m.ipConns, ip)
+		return
+	}
+	m.ipConns[ip]--
+}
+
 func (m *Manager) Register(client *Client) {
 	m.register <- client
 }
@@ -58,10 +286,26 @@ func (m *Manager) Unregister(client *Client) {
 	m.unregister <- client
 }
 
+// Broadcast sends a message to every connected client
+func (m *Manager) Broadcast(msgType string, payload any) {
+	m.broadcast <- outboundMessage{Type: msgType, Payload: payload}
+}
+
 func (m *Manager) Run() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
+	reactionTicker := time.NewTicker(reactionTickInterval)
+	defer reactionTicker.Stop()
+
+	// Without a cluster the heartbeat channel stays nil and never fires
+	var clusterTick <-chan time.Time
+	if m.config.Cluster != nil {
+		clusterTicker := time.NewTicker(clusterHeartbeat)
+		defer clusterTicker.Stop()
+		clusterTick = clusterTicker.C
+	}
+
 	for {
 		select {
 		case client := <-m.register:
@@ -69,85 +313,224 @@ func (m *Manager) Run() {
 			m.clients[client.ID] = client
 			m.mu.Unlock()
 
+			// Catch the new client up on the conversation, unless it is an
+			// event stream that can resume where it left off
+			if !m.resume(client) {
+				m.sendChatHistory(client)
+				m.sendChatSettings(client)
+			}
+			// The next broadcast may be coalesced away, so the new client
+			// gets the last count directly
+			client.SendMessage(TypeViewerCount, m.lastCount)
+
 			// Send current viewer count to all clients
-			m.broadcastViewerCount()
+			m.viewerCountChanged()
 
 		case client := <-m.unregister:
-			m.mu.Lock()
-			if _, ok := m.clients[client.ID]; ok {
-				delete(m.clients, client.ID)
-				close(client.Send)
-				m.mu.Unlock()
+			m.removeClient(client)
 
-				// Send updated viewer count to all clients
-				m.broadcastViewerCount()
-			} else {
-				m.mu.Unlock()
+		case count := <-m.external:
+			if m.externalViewers.Swap(int64(count)) != int64(count) {
+				m.viewerCountChanged()
 			}
 
-		case <-ticker.C:
+		case <-m.countChanged:
+			m.viewerCountChanged()
+
+		case <-m.countDue:
+			m.countDue = nil
+			m.flushViewerCount()
+
+		case msg := <-m.broadcast:
+			m.broadcastMessage(msg.Type, msg.Payload)
+			m.publishMessage(msg.Type, msg.Payload)
+
+		case msg := <-m.chat:
+			m.appendChatHistory(msg)
+			m.broadcastMessage(TypeChat, msg)
+			m.publishMessage(TypeChat, msg)
+
+		case event := <-m.remote:
+			m.applyClusterEvent(event, time.Now())
+
+		case now := <-clusterTick:
+			m.publishCounts()
+			if m.expirePeers(now) {
+				m.scheduleViewerCount()
+			}
+
+		case name := <-m.reactions:
+			m.reactionAgg.second[name]++
+
+		case now := <-reactionTicker.C:
+			// Broadcast aggregated reactions instead of relaying each one
+			m.tickReactions(now)
+
+		case now := <-ticker.C:
 			// Ping all clients to keep connection alive
 			m.pingClients()
+			m.pruneSlowMode(now)
 		}
 	}
 }
 
+// GetViewerCount returns the headline viewer count (unique viewers plus external readers)
 func (m *Manager) GetViewerCount() int {
+	return m.GetViewerCounts().Total()
+}
+
+// GetViewerCounts returns the audience across all replicas. Viewers connected
+// to several replicas at once are counted once per replica.
+func (m *Manager) GetViewerCounts() ViewerCounts {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return len(m.clients)
-}
 
-func (m *Manager) broadcastViewerCount() {
-	count := m.GetViewerCount()
-	message := []byte(`{"type":"viewer_count","count":` + strconv.Itoa(count) + `}`)
+	counts := m.localViewerCountsLocked()
+	for _, peer := range m.peers {
+		counts.Connections += peer.counts.Connections
+		counts.UniqueViewers += peer.counts.UniqueViewers
+	}
+	// Every replica polls the same MediaMTX, so external readers are not summed
+	return counts
+}
 
+// localViewerCounts returns the audience connected to this replica
+func (m *Manager) localViewerCounts() ViewerCounts {
 	m.mu.RLock()
-	clients := make([]*Client, 0, len(m.clients))
+	defer m.mu.RUnlock()
+	return m.localViewerCountsLocked()
+}
+
+func (m *Manager) localViewerCountsLocked() ViewerCounts {
+	viewers := make(map[string]struct{}, len(m.clients))
 	for _, client := range m.clients {
-		clients = append(clients, client)
+		if client.IsPlaying() {
+			viewers[client.ViewerID] = struct{}{}
+		}
 	}
-	m.mu.RUnlock()
 
-	for _, client := range clients {
-		select {
-		case client.Send <- message:
-		default:
-			// Client's send channel is full, skip
-		}
+	return ViewerCounts{
+		Connections:     len(m.clients),
+		UniqueViewers:   len(viewers),
+		ExternalViewers: int(m.externalViewers.Load()),
 	}
 }
 
-func (m *Manager) pingClients() {
+// viewerCountChanged announces a change of this replica's audience to
+// local clients and other replicas. Only called from the run loop.
+func (m *Manager) viewerCountChanged() {
+	m.countPublish = true
+	m.scheduleViewerCount()
+}
+
+// scheduleViewerCount arms the debounce timer unless a broadcast is already
+// pending, so that a burst of joins and leaves results in one broadcast.
+// Only called from the run loop.
+func (m *Manager) scheduleViewerCount() {
+	if m.countDue != nil {
+		return
+	}
+	if m.countTimer == nil {
+		m.countTimer = time.NewTimer(m.config.ViewerCountDebounce)
+	} else {
+		m.countTimer.Reset(m.config.ViewerCountDebounce)
+	}
+	m.countDue = m.countTimer.C
+}
+
+// flushViewerCount sends the coalesced viewer count. Only called from the run loop.
+func (m *Manager) flushViewerCount() {
+	if m.countPublish {
+		m.countPublish = false
+		m.publishCounts()
+	}
+
+	counts := m.GetViewerCounts()
+	payload := ViewerCountPayload{
+		Count:       counts.Total(),
+		Connections: counts.Connections,
+	}
+	// Joins and leaves that cancel out need no broadcast
+	if payload == m.lastCount {
+		return
+	}
+	m.lastCount = payload
+	m.broadcastMessage(TypeViewerCount, payload)
+}
+
+// clientSnapshot copies the registered clients into a reused buffer so that
+// frames can be queued without holding mu. Only called from the run loop.
+func (m *Manager) clientSnapshot() []*Client {
 	m.mu.RLock()
-	clients := make([]*Client, 0, len(m.clients))
+	clients := m.snapshot[:0]
 	for _, client := range m.clients {
 		clients = append(clients, client)
 	}
 	m.mu.RUnlock()
 
-	pingMessage := []byte(`{"type":"ping"}`)
-	for _, client := range clients {
-		select {
-		case client.Send <- pingMessage:
-			client.SetLastPing(time.Now())
-		default:
+	m.snapshot = clients
+	return clients
+}
+
+// sendToAll queues a frame for every client and drops the ones that could
+// not take it. Only called from the run loop.
+func (m *Manager) sendToAll(frame *Frame) {
+	for _, client := range m.clientSnapshot() {
+		if !client.trySend(frame) {
+			m.removeClient(client)
+		}
+	}
+	clear(m.snapshot)
+}
+
+func (m *Manager) pingClients() {
+	now := time.Now()
+	for _, client := range m.clientSnapshot() {
+		if client.trySend(m.ping) {
+			client.SetLastPing(now)
+		} else {
 			// Client is not responsive, disconnect
-			// Use goroutine to avoid deadlock since we're on the same goroutine as Run()
-			go func(c *Client) {
-				m.unregister <- c
-			}(client)
+			m.removeClient(client)
 		}
 	}
+	clear(m.snapshot)
 }
 
-func NewClient(conn *websocket.Conn, manager *Manager) *Client {
+// removeClient drops a client and releases its IP slot. It is safe to call
+// for clients that are already gone. Only called from the run loop.
+func (m *Manager) removeClient(client *Client) {
+	m.mu.Lock()
+	_, ok := m.clients[client.ID]
+	delete(m.clients, client.ID)
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	client.closeSend()
+	m.ReleaseIP(client.IP)
+
+	// Send updated viewer count to all clients
+	m.viewerCountChanged()
+}
+
+// NewClient creates a client for an upgraded connection. The caller must
+// already hold an IP slot from AcquireIP, which is released on unregister.
+// Clients count as viewers until they report that their player stopped.
+func NewClient(conn *websocket.Conn, manager *Manager, ip, viewerID string) *Client {
 	return &Client{
 		ID:       uuid.New().String(),
+		IP:       ip,
+		ViewerID: viewerID,
 		Conn:     conn,
 		Manager:  manager,
-		Send:     make(chan []byte, 256),
+		Send:     make(chan *Frame, sendBufferSize),
 		lastPing: time.Now(),
+		playing:  true,
+		nickname: defaultNickname(viewerID),
+
+		chatLimiter:     newRateLimiter(manager.config.Chat.RateBurst, manager.config.Chat.RateInterval),
+		reactionLimiter: newRateLimiter(manager.config.Reactions.RateBurst, manager.config.Reactions.RateInterval),
 	}
 }
 
@@ -157,6 +540,7 @@ func (c *Client) ReadPump() {
 		c.Conn.Close()
 	}()
 
+	c.Conn.SetReadLimit(c.Manager.config.MaxMessageSize)
 	if err := c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
 		c.Manager.logger.Errorf("Failed to set read deadline: %v", err)
 		return
@@ -171,16 +555,16 @@ func (c *Client) ReadPump() {
 	for {
 		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				c.Manager.metrics.RecordRejection(RejectMessageTooLarge)
+				c.Manager.logger.Warnf("Closing client %s (%s): message exceeds %d bytes", c.ID, c.IP, c.Manager.config.MaxMessageSize)
+			} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.Manager.logger.Errorf("WebSocket error: %v", err)
 			}
 			break
 		}
 
-		// Handle pong messages
-		if string(message) == `{"type":"pong"}` {
-			c.SetLastPing(time.Now())
-		}
+		c.handleMessage(message)
 	}
 }
 
@@ -193,13 +577,18 @@ func (c *Client) WritePump() {
 
 	for {
 		select {
-		case message, ok := <-c.Send:
+		case frame, ok := <-c.Send:
 			if err := c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
 				c.Manager.logger.Errorf("Failed to set write deadline: %v", err)
 				return
 			}
 			if !ok {
-				if err := c.Conn.WriteMessage(websocket.CloseMessage, []byte{}); err != nil {
+				// Ask evicted clients to back off before reconnecting
+				closeMessage := []byte{}
+				if c.isEvicted() {
+					closeMessage = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "slow consumer")
+				}
+				if err := c.Conn.WriteMessage(websocket.CloseMessage, closeMessage); err != nil {
 					if isExpectedCloseError(err) {
 						c.Manager.logger.Debugf("Connection already closed: %v", err)
 					} else {
@@ -209,7 +598,13 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			var err error
+			if frame.prepared != nil {
+				err = c.Conn.WritePreparedMessage(frame.prepared)
+			} else {
+				err = c.Conn.WriteMessage(websocket.TextMessage, frame.Data)
+			}
+			if err != nil {
 				return
 			}
 
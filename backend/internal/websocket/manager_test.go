@@ -0,0 +1,389 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// benchClients is the audience size the manager is expected to handle
+const benchClients = 10000
+
+const typeBench = "bench"
+
+type benchPayload struct {
+	Body string `json:"body"`
+}
+
+func newTestManager(t testing.TB, debounce time.Duration) *Manager {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	manager := NewManager(Config{ViewerCountDebounce: debounce}, logger)
+	go manager.Run()
+	return manager
+}
+
+// waitFor polls until cond holds or the timeout expires
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	return true
+}
+
+// streamReader drains an event stream client and counts the frames of one type
+type streamReader struct {
+	client   *Client
+	received atomic.Int64
+	done     chan struct{}
+
+	mu     sync.Mutex
+	frames []*Frame
+}
+
+func startStreamReader(manager *Manager, id int, countType string, keep bool) *streamReader {
+	r := &streamReader{
+		client: NewStreamClient(manager, fmt.Sprintf("10.0.%d.%d", id/256, id%256), fmt.Sprintf("viewer-%d", id), ""),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(r.done)
+		for frame := range r.client.Send {
+			if frame.Type != countType {
+				continue
+			}
+			if keep {
+				r.mu.Lock()
+				r.frames = append(r.frames, frame)
+				r.mu.Unlock()
+			}
+			r.received.Add(1)
+		}
+	}()
+	manager.Register(r.client)
+	return r
+}
+
+func stopStreamReaders(manager *Manager, readers []*streamReader) {
+	for _, r := range readers {
+		manager.Unregister(r.client)
+	}
+	for _, r := range readers {
+		<-r.done
+	}
+}
+
+// lastViewerCount decodes the newest viewer count the reader kept
+func (r *streamReader) lastViewerCount(t testing.TB) ViewerCountPayload {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.frames) == 0 {
+		t.Fatal("no viewer count received")
+	}
+	var env Envelope
+	var payload ViewerCountPayload
+	if err := json.Unmarshal(r.frames[len(r.frames)-1].Data, &env); err != nil {
+		t.Fatalf("failed to decode envelope: %v", err)
+	}
+	if err := json.Unmarshal(env.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode viewer count: %v", err)
+	}
+	return payload
+}
+
+// hijackResponse lets the upgrader take over one end of a net.Pipe
+type hijackResponse struct {
+	header http.Header
+	conn   net.Conn
+	brw    *bufio.ReadWriter
+}
+
+func (r *hijackResponse) Header() http.Header         { return r.header }
+func (r *hijackResponse) Write(p []byte) (int, error) { return r.conn.Write(p) }
+func (r *hijackResponse) WriteHeader(int)             {}
+
+func (r *hijackResponse) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, r.brw, nil
+}
+
+// wsViewer is a viewer connected over a real WebSocket. The connection runs
+// over an in-memory pipe, so thousands of them need no file descriptors.
+type wsViewer struct {
+	conn     *websocket.Conn
+	received *atomic.Int64
+	done     chan struct{}
+}
+
+var (
+	testUpgrader = websocket.Upgrader{ReadBufferSize: 512, WriteBufferSize: 512}
+	testDialer   = websocket.Dialer{ReadBufferSize: 512, WriteBufferSize: 512}
+)
+
+// connectViewer connects a viewer through the same pumps as HandleWebSocket
+// and counts the frames of countType it reads into received
+func connectViewer(t testing.TB, manager *Manager, id int, countType string, received *atomic.Int64) *wsViewer {
+	t.Helper()
+
+	serverEnd, clientEnd := net.Pipe()
+	upgraded := make(chan error, 1)
+	go func() {
+		brw := bufio.NewReadWriter(bufio.NewReader(serverEnd), bufio.NewWriter(serverEnd))
+		req, err := http.ReadRequest(brw.Reader)
+		if err != nil {
+			upgraded <- err
+			return
+		}
+		conn, err := testUpgrader.Upgrade(&hijackResponse{header: http.Header{}, conn: serverEnd, brw: brw}, req, nil)
+		if err != nil {
+			upgraded <- err
+			return
+		}
+		client := NewClient(conn, manager, fmt.Sprintf("10.0.%d.%d", id/256, id%256), fmt.Sprintf("viewer-%d", id))
+		manager.Register(client)
+		go client.WritePump()
+		go client.ReadPump()
+		upgraded <- nil
+	}()
+
+	dialer := testDialer
+	dialer.NetDialContext = func(context.Context, string, string) (net.Conn, error) { return clientEnd, nil }
+	conn, resp, err := dialer.Dial("ws://viewer.test/api/v1/ws", nil)
+	if err != nil {
+		t.Fatalf("failed to connect viewer %d: %v", id, err)
+	}
+	_ = resp.Body.Close()
+	if err := <-upgraded; err != nil {
+		t.Fatalf("failed to upgrade viewer %d: %v", id, err)
+	}
+
+	v := &wsViewer{conn: conn, received: received, done: make(chan struct{})}
+	go func() {
+		defer close(v.done)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var env Envelope
+			if json.Unmarshal(data, &env) == nil && env.Type == countType {
+				received.Add(1)
+			}
+		}
+	}()
+	return v
+}
+
+func connectViewers(t testing.TB, manager *Manager, n int, received *atomic.Int64) []*wsViewer {
+	t.Helper()
+
+	viewers := make([]*wsViewer, n)
+	for i := range viewers {
+		viewers[i] = connectViewer(t, manager, i, typeBench, received)
+	}
+	if !waitFor(30*time.Second, func() bool { return manager.localViewerCounts().Connections == n }) {
+		t.Fatalf("%d of %d viewers registered", manager.localViewerCounts().Connections, n)
+	}
+	return viewers
+}
+
+func disconnectViewers(t testing.TB, manager *Manager, viewers []*wsViewer) {
+	t.Helper()
+
+	for _, v := range viewers {
+		_ = v.conn.Close()
+	}
+	for _, v := range viewers {
+		<-v.done
+	}
+	if !waitFor(30*time.Second, func() bool { return manager.localViewerCounts().Connections == 0 }) {
+		t.Fatalf("%d viewers still registered", manager.localViewerCounts().Connections)
+	}
+}
+
+// TestBroadcastReachesEveryViewer writes broadcasts through the prepared
+// message path of 10k WebSocket connections and expects every frame to
+// arrive. Each viewer's buffer holds all of them, so none may be evicted.
+func TestBroadcastReachesEveryViewer(t *testing.T) {
+	const messages = 20
+
+	manager := newTestManager(t, time.Hour)
+	var received atomic.Int64
+	viewers := connectViewers(t, manager, benchClients, &received)
+	defer disconnectViewers(t, manager, viewers)
+
+	for i := 0; i < messages; i++ {
+		manager.Broadcast(typeBench, benchPayload{Body: fmt.Sprintf("message %d", i)})
+	}
+
+	want := int64(messages * benchClients)
+	if !waitFor(30*time.Second, func() bool { return received.Load() == want }) {
+		t.Fatalf("viewers received %d of %d frames", received.Load(), want)
+	}
+	if evicted := manager.Metrics().Rejections(RejectSlowConsumer); evicted != 0 {
+		t.Fatalf("%d viewers were evicted", evicted)
+	}
+}
+
+// TestViewerCountCoalesced connects and disconnects 10k clients and expects
+// at most one viewer count broadcast per debounce interval
+func TestViewerCountCoalesced(t *testing.T) {
+	const debounce = 100 * time.Millisecond
+
+	manager := newTestManager(t, debounce)
+	observer := startStreamReader(manager, benchClients, TypeViewerCount, true)
+	defer stopStreamReaders(manager, []*streamReader{observer})
+	// The observer gets the current count directly when it registers
+	if !waitFor(5*time.Second, func() bool { return observer.received.Load() == 1 }) {
+		t.Fatal("observer did not get the initial viewer count")
+	}
+	time.Sleep(2 * debounce)
+	before := observer.received.Load()
+
+	start := time.Now()
+	readers := make([]*streamReader, benchClients)
+	for i := range readers {
+		readers[i] = startStreamReader(manager, i, "", false)
+	}
+	stopStreamReaders(manager, readers)
+
+	settled := waitFor(10*time.Second, func() bool {
+		observer.mu.Lock()
+		defer observer.mu.Unlock()
+		return observer.received.Load() > before && len(observer.frames) > 0 &&
+			manager.localViewerCounts().Connections == 1
+	})
+	if !settled {
+		t.Fatal("viewer counts did not settle")
+	}
+	time.Sleep(2 * debounce)
+	elapsed := time.Since(start)
+
+	broadcasts := observer.received.Load() - before
+	if limit := int64(elapsed/debounce) + 1; broadcasts > limit {
+		t.Fatalf("%d viewer count broadcasts in %s, want at most %d", broadcasts, elapsed, limit)
+	}
+	if got := observer.lastViewerCount(t); got.Connections != 1 {
+		t.Fatalf("last viewer count has %d connections, want 1", got.Connections)
+	}
+}
+
+// TestSlowConsumerEvicted fills the buffer of a client that never reads and
+// expects it to be evicted by exactly the broadcast that overflows it, while
+// a reading client gets every message
+func TestSlowConsumerEvicted(t *testing.T) {
+	// No viewer count broadcasts interfere with the buffer arithmetic
+	manager := newTestManager(t, time.Hour)
+
+	reader := startStreamReader(manager, 0, typeBench, false)
+	defer stopStreamReaders(manager, []*streamReader{reader})
+	stalled := NewStreamClient(manager, "10.1.0.1", "slow", "")
+	manager.Register(stalled)
+
+	// Frames reach clients in run loop order, so once the reader has a
+	// broadcast the stalled client has it and its catch-up messages too
+	sent := int64(0)
+	broadcast := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			manager.Broadcast(typeBench, benchPayload{Body: "Thanks for listening"})
+		}
+		sent += int64(n)
+		if !waitFor(5*time.Second, func() bool { return reader.received.Load() == sent }) {
+			t.Fatalf("reader received %d of %d broadcasts", reader.received.Load(), sent)
+		}
+	}
+
+	broadcast(1)
+	broadcast(cap(stalled.Send) - len(stalled.Send))
+	if stalled.isEvicted() {
+		t.Fatal("client evicted before its buffer overflowed")
+	}
+	if got := manager.Metrics().Rejections(RejectSlowConsumer); got != 0 {
+		t.Fatalf("%d slow consumer rejections before overflow, want 0", got)
+	}
+
+	broadcast(1)
+	if !stalled.isEvicted() {
+		t.Fatal("client not evicted after its buffer overflowed")
+	}
+	if got := manager.Metrics().Rejections(RejectSlowConsumer); got != 1 {
+		t.Fatalf("%d slow consumer rejections, want 1", got)
+	}
+	if got := manager.localViewerCounts().Connections; got != 1 {
+		t.Fatalf("%d clients registered after eviction, want 1", got)
+	}
+	for range stalled.Send {
+		// The buffered frames drain and the channel is closed
+	}
+}
+
+// BenchmarkBroadcast measures the time until one broadcast was written to
+// 10k WebSocket connections and read by every viewer
+func BenchmarkBroadcast(b *testing.B) {
+	manager := newTestManager(b, time.Hour)
+	var received atomic.Int64
+	viewers := connectViewers(b, manager, benchClients, &received)
+	defer disconnectViewers(b, manager, viewers)
+
+	payload := benchPayload{Body: "The next DJ starts in five minutes"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		received.Store(0)
+		manager.Broadcast(typeBench, payload)
+		if !waitFor(30*time.Second, func() bool { return received.Load() == benchClients }) {
+			b.Fatalf("broadcast reached %d of %d viewers", received.Load(), benchClients)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/benchClients, "ns/viewer")
+}
+
+// BenchmarkReconnectStorm connects 10k clients at once and disconnects them
+// all again, reporting the viewer count broadcasts an observer receives
+func BenchmarkReconnectStorm(b *testing.B) {
+	const debounce = 100 * time.Millisecond
+
+	manager := newTestManager(b, debounce)
+	observer := startStreamReader(manager, benchClients, TypeViewerCount, false)
+	defer stopStreamReaders(manager, []*streamReader{observer})
+
+	var broadcasts int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		before := observer.received.Load()
+		readers := make([]*streamReader, benchClients)
+		for j := range readers {
+			readers[j] = startStreamReader(manager, j, "", false)
+		}
+		stopStreamReaders(manager, readers)
+
+		// Pending broadcasts are not part of the storm's cost
+		b.StopTimer()
+		time.Sleep(2 * debounce)
+		broadcasts += observer.received.Load() - before
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(broadcasts)/float64(b.N), "broadcasts/op")
+}
@@ -0,0 +1,68 @@
+package websocket
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Reasons a viewer connection can be rejected or dropped
+const (
+	RejectOrigin          = "origin"
+	RejectIPLimit         = "ip_limit"
+	RejectMessageTooLarge = "message_too_large"
+	RejectSlowConsumer    = "slow_consumer"
+)
+
+var rejectReasons = []string{RejectOrigin, RejectIPLimit, RejectMessageTooLarge, RejectSlowConsumer}
+
+type Metrics struct {
+	rejected map[string]*atomic.Int64
+}
+
+func newMetrics() *Metrics {
+	m := &Metrics{
+		rejected: make(map[string]*atomic.Int64, len(rejectReasons)),
+	}
+	for _, reason := range rejectReasons {
+		m.rejected[reason] = &atomic.Int64{}
+	}
+	return m
+}
+
+func (m *Metrics) RecordRejection(reason string) {
+	if counter, ok := m.rejected[reason]; ok {
+		counter.Add(1)
+	}
+}
+
+func (m *Metrics) Rejections(reason string) int64 {
+	if counter, ok := m.rejected[reason]; ok {
+		return counter.Load()
+	}
+	return 0
+}
+
+// WriteMetrics writes the manager's counters in the Prometheus text exposition
+// format. Gauges describe this replica only; sum them across replicas.
+func (m *Manager) WriteMetrics(w io.Writer) {
+	counts := m.localViewerCounts()
+
+	fmt.Fprintln(w, "# HELP ws_connections_active Number of open viewer WebSocket connections.")
+	fmt.Fprintln(w, "# TYPE ws_connections_active gauge")
+	fmt.Fprintf(w, "ws_connections_active %d\n", counts.Connections)
+
+	fmt.Fprintln(w, "# HELP viewers_unique Distinct viewer identities with a playing player.")
+	fmt.Fprintln(w, "# TYPE viewers_unique gauge")
+	fmt.Fprintf(w, "viewers_unique %d\n", counts.UniqueViewers)
+
+	fmt.Fprintln(w, "# HELP viewers_external Non-HLS readers reported by MediaMTX.")
+	fmt.Fprintln(w, "# TYPE viewers_external gauge")
+	fmt.Fprintf(w, "viewers_external %d\n", counts.ExternalViewers)
+
+	fmt.Fprintln(w, "# HELP ws_connections_rejected_total Viewer WebSocket connections rejected or dropped by the server.")
+	fmt.Fprintln(w, "# TYPE ws_connections_rejected_total counter")
+	for _, reason := range rejectReasons {
+		fmt.Fprintf(w, "ws_connections_rejected_total{reason=%q} %d\n", reason, m.metrics.Rejections(reason))
+	}
+}
@@ -0,0 +1,524 @@
+package websocket
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type ModerationConfig struct {
+	// Moderators maps access tokens to moderator names
+	Moderators map[string]string
+	// BlockedWords are masked in chat messages (case-insensitive)
+	BlockedWords []string
+	// SubscriberMinAge is how old a viewer identity must be to chat while
+	// subscriber-only mode is enabled
+	SubscriberMinAge time.Duration
+	// OnAction is called for every moderation action, e.g. to persist bans
+	// and write the audit log. A ban is only applied once it succeeded.
+	OnAction func(ModerationAction) error
+	// LookupMessage resolves messages that are no longer in the history buffer
+	LookupMessage func(id uuid.UUID) (ChatMessage, bool)
+}
+
+// MaxTimeoutSeconds bounds timeouts, larger durations would overflow
+// time.Duration and turn into permanent bans
+const MaxTimeoutSeconds = 365 * 24 * 60 * 60
+
+// Moderation actions recorded in the audit log
+const (
+	ActionDeleteMessage = "delete_message"
+	ActionBan           = "ban"
+	ActionTimeout       = "timeout"
+	ActionUnban         = "unban"
+	ActionChatSettings  = "chat_settings"
+)
+
+var (
+	ErrMessageNotFound = errors.New("message not found")
+	ErrBanNotFound     = errors.New("ban not found")
+	ErrEmptyBanTarget  = errors.New("ban target is empty")
+	ErrBanNotSaved     = errors.New("ban could not be saved")
+)
+
+// Ban blocks a viewer identity and/or IP address from chatting.
+// A nil ExpiresAt means the ban is permanent; otherwise it is a timeout.
+type Ban struct {
+	ID        uuid.UUID
+	ViewerID  string
+	IP        string
+	Reason    string
+	ExpiresAt *time.Time
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+func (b Ban) Active(now time.Time) bool {
+	return b.ExpiresAt == nil || now.Before(*b.ExpiresAt)
+}
+
+func (b Ban) Matches(viewerID, ip string) bool {
+	return (b.ViewerID != "" && b.ViewerID == viewerID) || (b.IP != "" && b.IP == ip)
+}
+
+// BanTarget selects who to ban. When MessageID is set, the author of that
+// message is banned; IncludeIP extends the ban to the author's address.
+type BanTarget struct {
+	MessageID *uuid.UUID
+	ViewerID  string
+	IP        string
+	IncludeIP bool
+}
+
+type ChatSettings struct {
+	// SlowMode is the minimum interval between messages from one viewer
+	SlowMode time.Duration
+	// SubscriberOnly restricts chat to viewer identities older than SubscriberMinAge
+	SubscriberOnly bool
+}
+
+type ModerationAction struct {
+	Action    string
+	Moderator string
+	MessageID *uuid.UUID
+	Ban       *Ban
+	Settings  *ChatSettings
+	CreatedAt time.Time
+}
+
+type moderationState struct {
+	mu       sync.RWMutex
+	bans     map[uuid.UUID]Ban
+	settings ChatSettings
+	// lastChat holds each viewer's last accepted message time for slow mode
+	lastChat map[string]time.Time
+	// blocked holds the blocked words lowercased rune by rune
+	blocked [][]rune
+}
+
+func newModerationState(cfg ModerationConfig) *moderationState {
+	blocked := make([][]rune, 0, len(cfg.BlockedWords))
+	for _, word := range cfg.BlockedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			blocked = append(blocked, lowerRunes(word))
+		}
+	}
+
+	return &moderationState{
+		bans:     make(map[uuid.UUID]Ban),
+		lastChat: make(map[string]time.Time),
+		blocked:  blocked,
+	}
+}
+
+// setSettings replaces the chat settings. The slow mode history is only
+// needed while slow mode is on. The caller holds mu.
+func (s *moderationState) setSettings(settings ChatSettings) {
+	s.settings = settings
+	if settings.SlowMode <= 0 {
+		clear(s.lastChat)
+	}
+}
+
+func newChatSettingsPayload(settings ChatSettings) ChatSettingsPayload {
+	return ChatSettingsPayload{
+		SlowModeSeconds: int(settings.SlowMode / time.Second),
+		SubscriberOnly:  settings.SubscriberOnly,
+	}
+}
+
+// AuthenticateModerator returns the moderator name for an access token
+func (m *Manager) AuthenticateModerator(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	for candidate, name := range m.config.Moderation.Moderators {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// LoadBans restores persisted bans, e.g. at startup
+func (m *Manager) LoadBans(bans []Ban) {
+	m.moderation.mu.Lock()
+	defer m.moderation.mu.Unlock()
+
+	for _, ban := range bans {
+		m.moderation.bans[ban.ID] = ban
+	}
+}
+
+// ActiveBans returns all bans and timeouts that have not expired
+func (m *Manager) ActiveBans() []Ban {
+	m.moderation.mu.Lock()
+	defer m.moderation.mu.Unlock()
+
+	now := time.Now()
+	bans := make([]Ban, 0, len(m.moderation.bans))
+	for id, ban := range m.moderation.bans {
+		if !ban.Active(now) {
+			delete(m.moderation.bans, id)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	return bans
+}
+
+func (m *Manager) ChatSettings() ChatSettings {
+	m.moderation.mu.RLock()
+	defer m.moderation.mu.RUnlock()
+	return m.moderation.settings
+}
+
+func (m *Manager) SetChatSettings(moderator string, settings ChatSettings) {
+	m.moderation.mu.Lock()
+	m.moderation.setSettings(settings)
+	m.moderation.mu.Unlock()
+
+	m.Broadcast(TypeChatSettings, newChatSettingsPayload(settings))
+
+	m.recordAction(ModerationAction{
+		Action:    ActionChatSettings,
+		Moderator: moderator,
+		Settings:  &settings,
+	})
+}
+
+// DeleteChatMessage removes a message from the history buffer and tells
+// clients to hide it
+func (m *Manager) DeleteChatMessage(moderator string, id uuid.UUID) error {
+	found := m.removeChatHistory(id)
+	if !found && m.config.Moderation.LookupMessage != nil {
+		_, found = m.config.Moderation.LookupMessage(id)
+	}
+	if !found {
+		return ErrMessageNotFound
+	}
+
+	m.Broadcast(TypeChatDelete, ChatDeletePayload{ID: id})
+
+	m.recordAction(ModerationAction{
+		Action:    ActionDeleteMessage,
+		Moderator: moderator,
+		MessageID: &id,
+	})
+	return nil
+}
+
+// BanViewer bans or, with a positive duration, times out a viewer
+func (m *Manager) BanViewer(moderator string, target BanTarget, duration time.Duration, reason string) (Ban, error) {
+	viewerID, ip := target.ViewerID, target.IP
+	if target.MessageID != nil {
+		msg, ok := m.findChatMessage(*target.MessageID)
+		if !ok {
+			return Ban{}, ErrMessageNotFound
+		}
+		viewerID = msg.ViewerID
+		if target.IncludeIP {
+			ip = msg.IP
+		}
+	}
+	if viewerID == "" && ip == "" {
+		return Ban{}, ErrEmptyBanTarget
+	}
+
+	now := time.Now()
+	ban := Ban{
+		ID:        uuid.New(),
+		ViewerID:  viewerID,
+		IP:        ip,
+		Reason:    reason,
+		CreatedBy: moderator,
+		CreatedAt: now,
+	}
+	action := ActionBan
+	if duration > 0 {
+		expiresAt := now.Add(duration)
+		ban.ExpiresAt = &expiresAt
+		action = ActionTimeout
+	}
+
+	// Persist first, a ban that is not saved would be lost on restart
+	err := m.recordAction(ModerationAction{
+		Action:    action,
+		Moderator: moderator,
+		MessageID: target.MessageID,
+		Ban:       &ban,
+	})
+	if err != nil {
+		return Ban{}, ErrBanNotSaved
+	}
+
+	m.moderation.mu.Lock()
+	m.moderation.bans[ban.ID] = ban
+	m.moderation.mu.Unlock()
+	m.publish(ClusterEvent{Kind: ClusterBan, Ban: &ban})
+	return ban, nil
+}
+
+func (m *Manager) Unban(moderator string, id uuid.UUID) error {
+	m.moderation.mu.Lock()
+	ban, ok := m.moderation.bans[id]
+	delete(m.moderation.bans, id)
+	m.moderation.mu.Unlock()
+
+	if !ok {
+		return ErrBanNotFound
+	}
+	m.publish(ClusterEvent{Kind: ClusterUnban, BanID: &id})
+
+	m.recordAction(ModerationAction{
+		Action:    ActionUnban,
+		Moderator: moderator,
+		Ban:       &ban,
+	})
+	return nil
+}
+
+func (m *Manager) recordAction(action ModerationAction) error {
+	action.CreatedAt = time.Now()
+	m.logger.Infof("Moderation: %s by %s", action.Action, action.Moderator)
+	if m.config.Moderation.OnAction == nil {
+		return nil
+	}
+	if err := m.config.Moderation.OnAction(action); err != nil {
+		m.logger.Errorf("Failed to record moderation action %s: %v", action.Action, err)
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) findChatMessage(id uuid.UUID) (ChatMessage, bool) {
+	m.historyMu.Lock()
+	for _, msg := range m.chatHistory {
+		if msg.ID == id {
+			m.historyMu.Unlock()
+			return msg, true
+		}
+	}
+	m.historyMu.Unlock()
+
+	if m.config.Moderation.LookupMessage != nil {
+		return m.config.Moderation.LookupMessage(id)
+	}
+	return ChatMessage{}, false
+}
+
+// checkChatAllowed applies bans, subscriber-only mode and slow mode to a
+// message about to be sent. It returns an error code and message if the
+// client may not chat right now.
+func (m *Manager) checkChatAllowed(c *Client, now time.Time) (string, string) {
+	m.moderation.mu.Lock()
+	defer m.moderation.mu.Unlock()
+
+	for id, ban := range m.moderation.bans {
+		if !ban.Active(now) {
+			delete(m.moderation.bans, id)
+			continue
+		}
+		if ban.Matches(c.ViewerID, c.IP) {
+			if ban.ExpiresAt != nil {
+				return "TIMED_OUT", "You are timed out until " + ban.ExpiresAt.UTC().Format(time.RFC3339)
+			}
+			return "BANNED", "You are banned from chat"
+		}
+	}
+
+	settings := m.moderation.settings
+	if settings.SubscriberOnly {
+		since, ok := viewerSince(c.ViewerID)
+		if ok && now.Sub(since) < m.config.Moderation.SubscriberMinAge {
+			return "SUBSCRIBERS_ONLY", "Chat is in subscriber-only mode"
+		}
+	}
+
+	if settings.SlowMode > 0 {
+		if last, ok := m.moderation.lastChat[c.ViewerID]; ok && now.Sub(last) < settings.SlowMode {
+			return "SLOW_MODE", "Slow mode is enabled, please wait before sending another message"
+		}
+		m.moderation.lastChat[c.ViewerID] = now
+	}
+
+	return "", ""
+}
+
+// pruneSlowMode forgets viewers whose last message is older than the slow
+// mode interval, as those no longer hold anyone back. Called from the run
+// loop rather than per message to keep chat cheap.
+func (m *Manager) pruneSlowMode(now time.Time) {
+	m.moderation.mu.Lock()
+	defer m.moderation.mu.Unlock()
+
+	interval := m.moderation.settings.SlowMode
+	for viewerID, last := range m.moderation.lastChat {
+		if now.Sub(last) >= interval {
+			delete(m.moderation.lastChat, viewerID)
+		}
+	}
+}
+
+// filterBlockedWords masks blocked words with asterisks. Matching is done
+// rune by rune, since lowercasing a string can change its byte length
+// (e.g. "İ" or the Kelvin sign).
+func (m *Manager) filterBlockedWords(text string) string {
+	if len(m.moderation.blocked) == 0 {
+		return text
+	}
+
+	masked := []rune(text)
+	lower := lowerRunes(text)
+	for _, word := range m.moderation.blocked {
+		for i := 0; i+len(word) <= len(lower); {
+			if !slices.Equal(lower[i:i+len(word)], word) {
+				i++
+				continue
+			}
+			for j := i; j < i+len(word); j++ {
+				masked[j] = '*'
+				lower[j] = '*'
+			}
+			i += len(word)
+		}
+	}
+	return string(masked)
+}
+
+// lowerRunes lowercases each rune of s, keeping the rune count
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// viewerSince returns when a viewer identity was issued. Identities from
+// before time-ordered IDs were introduced report false.
+func viewerSince(viewerID string) (time.Time, bool) {
+	id, err := uuid.Parse(viewerID)
+	if err != nil || id.Version() != 7 {
+		return time.Time{}, false
+	}
+	sec, nsec := id.Time().UnixTime()
+	return time.Unix(sec, nsec), true
+}
+
+func (c *Client) handleModeratorAuth(token string) {
+	name, ok := c.Manager.AuthenticateModerator(token)
+	if !ok {
+		c.SendMessage(TypeModResult, ModResultPayload{Action: "auth", OK: false, Error: "invalid token"})
+		return
+	}
+
+	c.mu.Lock()
+	c.moderator = name
+	c.mu.Unlock()
+
+	c.SendMessage(TypeModResult, ModResultPayload{Action: "auth", OK: true})
+}
+
+// moderatorName returns the authenticated moderator's name, or "" for viewers
+func (c *Client) moderatorName() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.moderator
+}
+
+func (c *Client) sendModResult(action string, err error) {
+	result := ModResultPayload{Action: action, OK: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	c.SendMessage(TypeModResult, result)
+}
+
+// moderator returns the moderator name for mod_* commands, replying with an
+// error result if the client has not authenticated
+func (c *Client) requireModerator(action string) (string, bool) {
+	moderator := c.moderatorName()
+	if moderator == "" {
+		c.sendModResult(action, errors.New("not authenticated as moderator"))
+		return "", false
+	}
+	return moderator, true
+}
+
+func (c *Client) handleModDelete(p ModDeletePayload) {
+	moderator, ok := c.requireModerator(TypeModDelete)
+	if !ok {
+		return
+	}
+	if p.MessageID == nil {
+		c.sendModResult(TypeModDelete, errors.New("messageId is required"))
+		return
+	}
+	c.sendModResult(TypeModDelete, c.Manager.DeleteChatMessage(moderator, *p.MessageID))
+}
+
+func (c *Client) handleModBan(p ModBanPayload) {
+	moderator, ok := c.requireModerator(TypeModBan)
+	if !ok {
+		return
+	}
+	if p.MessageID == nil {
+		c.sendModResult(TypeModBan, errors.New("messageId is required"))
+		return
+	}
+	if p.DurationSeconds < 0 || p.DurationSeconds > MaxTimeoutSeconds {
+		c.sendModResult(TypeModBan, fmt.Errorf("durationSeconds must be between 0 and %d", MaxTimeoutSeconds))
+		return
+	}
+
+	ban, err := c.Manager.BanViewer(moderator, BanTarget{
+		MessageID: p.MessageID,
+		IncludeIP: p.IncludeIP,
+	}, time.Duration(p.DurationSeconds)*time.Second, p.Reason)
+	if err != nil {
+		c.sendModResult(TypeModBan, err)
+		return
+	}
+	c.SendMessage(TypeModResult, ModResultPayload{Action: TypeModBan, OK: true, BanID: &ban.ID})
+}
+
+func (c *Client) handleModUnban(p ModUnbanPayload) {
+	moderator, ok := c.requireModerator(TypeModUnban)
+	if !ok {
+		return
+	}
+	if p.BanID == nil {
+		c.sendModResult(TypeModUnban, errors.New("banId is required"))
+		return
+	}
+	c.sendModResult(TypeModUnban, c.Manager.Unban(moderator, *p.BanID))
+}
+
+func (c *Client) handleModSettings(p ModSettingsPayload) {
+	moderator, ok := c.requireModerator(TypeModSettings)
+	if !ok {
+		return
+	}
+
+	settings := c.Manager.ChatSettings()
+	if p.SlowModeSeconds != nil {
+		if *p.SlowModeSeconds < 0 {
+			c.sendModResult(TypeModSettings, errors.New("slowModeSeconds must not be negative"))
+			return
+		}
+		settings.SlowMode = time.Duration(*p.SlowModeSeconds) * time.Second
+	}
+	if p.SubscriberOnly != nil {
+		settings.SubscriberOnly = *p.SubscriberOnly
+	}
+	c.Manager.SetChatSettings(moderator, settings)
+	c.sendModResult(TypeModSettings, nil)
+}
@@ -0,0 +1,37 @@
+package websocket
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFilterBlockedWords(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	manager := NewManager(Config{Moderation: ModerationConfig{BlockedWords: []string{"spam", " Scam ", "ok"}}}, logger)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain", text: "no spam please", want: "no **** please"},
+		{name: "mixed case", text: "SPAM and ScAm", want: "**** and ****"},
+		{name: "repeated", text: "spamspam", want: "********"},
+		{name: "next to dotted capital I", text: "İ spam", want: "İ ****"},
+		{name: "next to Kelvin sign", text: "K spam K", want: "K **** K"},
+		{name: "Japanese", text: "これはspamです", want: "これは****です"},
+		{name: "Kelvin sign in the word", text: "O\u212a!", want: "**!"},
+		{name: "clean", text: "hello", want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.filterBlockedWords(tt.text); got != tt.want {
+				t.Errorf("filterBlockedWords(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
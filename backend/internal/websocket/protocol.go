@@ -0,0 +1,382 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// ProtocolVersion is the version of the viewer WebSocket protocol described
+// in api/asyncapi.yaml. It changes only on incompatible payload changes.
+const ProtocolVersion = 1
+
+// Envelope wraps every message in both directions.
+//
+// Seq numbers the broadcast event stream: each message sent to all clients
+// gets the next number, so a gap tells a client that it missed events.
+// Messages addressed to a single client carry no seq.
+type Envelope struct {
+	Type    string          `json:"type"`
+	Version int             `json:"version"`
+	Seq     uint64          `json:"seq,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Server to client message types
+const (
+	TypeViewerToken   = "viewer_token"
+	TypeViewerCount   = "viewer_count"
+	TypeChatHistory   = "chat_history"
+	TypeChat          = "chat"
+	TypeChatDelete    = "chat_delete"
+	TypeChatSettings  = "chat_settings"
+	TypeChatError     = "chat_error"
+	TypeNickname      = "nickname"
+	TypeReactions     = "reactions"
+	TypeModResult     = "mod_result"
+	TypeLineupChanged = "lineup_changed"
+	TypeWaitlistOffer = "waitlist_offer"
+	TypeNowPlaying    = "now_playing"
+	TypeSlotEnded     = "slot_ended"
+	TypePing          = "ping"
+	TypeError         = "error"
+)
+
+// Client to server message types. "chat" is shared with the server type.
+const (
+	TypePong          = "pong"
+	TypePlayerState   = "player_state"
+	TypeSetNickname   = "set_nickname"
+	TypeReaction      = "reaction"
+	TypeModeratorAuth = "moderator_auth"
+	TypeModDelete     = "mod_delete"
+	TypeModBan        = "mod_ban"
+	TypeModUnban      = "mod_unban"
+	TypeModSettings   = "mod_settings"
+)
+
+// Codes sent in error messages
+const (
+	ErrorInvalidMessage     = "INVALID_MESSAGE"
+	ErrorInvalidPayload     = "INVALID_PAYLOAD"
+	ErrorUnknownType        = "UNKNOWN_TYPE"
+	ErrorUnsupportedVersion = "UNSUPPORTED_VERSION"
+)
+
+type ViewerTokenPayload struct {
+	Token string `json:"token"`
+}
+
+type ViewerCountPayload struct {
+	// Count is the headline viewer count (unique viewers plus external readers)
+	Count       int `json:"count"`
+	Connections int `json:"connections"`
+}
+
+type ChatHistoryPayload struct {
+	Messages []ChatMessage `json:"messages"`
+}
+
+type ChatDeletePayload struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type ChatSettingsPayload struct {
+	SlowModeSeconds int  `json:"slowModeSeconds"`
+	SubscriberOnly  bool `json:"subscriberOnly"`
+}
+
+type ChatErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type NicknamePayload struct {
+	Nickname string `json:"nickname"`
+}
+
+type ReactionsPayload struct {
+	Counts map[string]int `json:"counts"`
+	// Hype is a moving average of reactions per second
+	Hype float64 `json:"hype"`
+}
+
+type ModResultPayload struct {
+	Action string `json:"action"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+	// BanID is set on successful mod_ban results so the ban can be lifted
+	BanID *uuid.UUID `json:"banId,omitempty"`
+}
+
+// Reasons for lineup_changed messages
+const (
+	LineupCreated  = "created"
+	LineupDeleted  = "deleted"
+	LineupHeld     = "held"
+	LineupReleased = "released"
+)
+
+// LineupChangedPayload tells clients which time range of the timetable
+// changed so that they refetch availability for that window only
+type LineupChangedPayload struct {
+	Reason string `json:"reason"`
+	// ReservationID is set for reservation changes, not for holds
+	ReservationID *uuid.UUID `json:"reservationId,omitempty"`
+	StartTime     time.Time  `json:"startTime"`
+	EndTime       time.Time  `json:"endTime"`
+}
+
+// WaitlistOfferPayload announces that a waitlisted DJ can book a freed
+// range until ExpiresAt, using their waitlist token as the hold token
+type WaitlistOfferPayload struct {
+	EntryID   uuid.UUID `json:"entryId"`
+	DJName    string    `json:"djName"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NowPlayingPayload announces the track the DJ on air just submitted
+type NowPlayingPayload struct {
+	TrackID       uuid.UUID `json:"trackId"`
+	ReservationID uuid.UUID `json:"reservationId"`
+	DJName        string    `json:"djName"`
+	Artist        string    `json:"artist"`
+	Title         string    `json:"title"`
+	PlayedAt      time.Time `json:"playedAt"`
+}
+
+// SlotEndedPayload announces that a reservation's slot and grace period are
+// over. The next DJ, if any, can start publishing.
+type SlotEndedPayload struct {
+	ReservationID uuid.UUID `json:"reservationId"`
+	DJName        string    `json:"djName"`
+	EndTime       time.Time `json:"endTime"`
+	// PublisherKicked is set when the DJ was still publishing and got
+	// disconnected
+	PublisherKicked   bool       `json:"publisherKicked"`
+	NextReservationID *uuid.UUID `json:"nextReservationId,omitempty"`
+	NextDJName        *string    `json:"nextDjName,omitempty"`
+	NextStartTime     *time.Time `json:"nextStartTime,omitempty"`
+}
+
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Type echoes the type of the message that caused the error
+	Type string `json:"type,omitempty"`
+}
+
+type PlayerStatePayload struct {
+	Playing *bool `json:"playing"`
+}
+
+type ChatSendPayload struct {
+	Body string `json:"body"`
+}
+
+type SetNicknamePayload struct {
+	Nickname string `json:"nickname"`
+}
+
+type ReactionPayload struct {
+	Reaction string `json:"reaction"`
+}
+
+type ModeratorAuthPayload struct {
+	Token string `json:"token"`
+}
+
+type ModDeletePayload struct {
+	MessageID *uuid.UUID `json:"messageId"`
+}
+
+type ModBanPayload struct {
+	MessageID *uuid.UUID `json:"messageId"`
+	// DurationSeconds > 0 creates a timeout instead of a permanent ban
+	DurationSeconds int    `json:"durationSeconds"`
+	Reason          string `json:"reason"`
+	IncludeIP       bool   `json:"includeIp"`
+}
+
+type ModUnbanPayload struct {
+	BanID *uuid.UUID `json:"banId"`
+}
+
+// ModSettingsPayload changes only the fields that are present
+type ModSettingsPayload struct {
+	SlowModeSeconds *int  `json:"slowModeSeconds"`
+	SubscriberOnly  *bool `json:"subscriberOnly"`
+}
+
+// outboundMessage is a message waiting to be encoded by the run loop
+type outboundMessage struct {
+	Type    string
+	Payload any
+}
+
+// Frame is an encoded server message. Frames sent to many clients carry a
+// PreparedMessage so that WebSocket framing happens once, not per client.
+type Frame struct {
+	Type string
+	// Seq is the broadcast sequence number (0 for direct messages)
+	Seq  uint64
+	Data []byte
+
+	prepared *websocket.PreparedMessage
+}
+
+// newFrame encodes a message. A zero seq is omitted. Shared frames are
+// prepared for writing to many WebSocket connections.
+func newFrame(msgType string, seq uint64, payload any, shared bool) (*Frame, error) {
+	var raw json.RawMessage
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s payload: %w", msgType, err)
+		}
+		raw = data
+	}
+
+	data, err := json.Marshal(Envelope{
+		Type:    msgType,
+		Version: ProtocolVersion,
+		Seq:     seq,
+		Payload: raw,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", msgType, err)
+	}
+
+	frame := &Frame{Type: msgType, Seq: seq, Data: data}
+	if shared {
+		frame.prepared, err = websocket.NewPreparedMessage(websocket.TextMessage, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare %s: %w", msgType, err)
+		}
+	}
+	return frame, nil
+}
+
+// SendMessage queues a message for this client only. It returns false if the
+// message was dropped.
+func (c *Client) SendMessage(msgType string, payload any) bool {
+	frame, err := newFrame(msgType, 0, payload, false)
+	if err != nil {
+		c.Manager.logger.Errorf("Failed to encode message: %v", err)
+		return false
+	}
+	return c.trySend(frame)
+}
+
+func (c *Client) sendError(code, message, msgType string) {
+	c.SendMessage(TypeError, ErrorPayload{Code: code, Message: message, Type: msgType})
+}
+
+// broadcastMessage encodes a message with the next sequence number and
+// queues it for every client. Only called from the run loop so that
+// sequence numbers reach clients in order.
+func (m *Manager) broadcastMessage(msgType string, payload any) {
+	m.broadcastSeq++
+	frame, err := newFrame(msgType, m.broadcastSeq, payload, true)
+	if err != nil {
+		m.logger.Errorf("Failed to encode message: %v", err)
+		return
+	}
+	m.rememberBroadcast(frame)
+	m.sendToAll(frame)
+}
+
+// handleMessage decodes an inbound envelope and dispatches it by type
+func (c *Client) handleMessage(data []byte) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Type == "" {
+		c.sendError(ErrorInvalidMessage, "Message must be a JSON envelope with a type", "")
+		return
+	}
+	// A missing version is treated as the current one
+	if env.Version != 0 && env.Version != ProtocolVersion {
+		c.sendError(ErrorUnsupportedVersion, fmt.Sprintf("Unsupported protocol version %d, server speaks %d", env.Version, ProtocolVersion), env.Type)
+		return
+	}
+
+	switch env.Type {
+	case TypePong:
+		c.SetLastPing(time.Now())
+
+	case TypePlayerState:
+		var p PlayerStatePayload
+		if c.decodePayload(env, &p) && p.Playing != nil && c.SetPlaying(*p.Playing) {
+			// Sent by the frontend when the player starts or stops
+			c.Manager.notifyCountChanged()
+		}
+
+	case TypeChat:
+		var p ChatSendPayload
+		if c.decodePayload(env, &p) {
+			c.handleChat(p.Body)
+		}
+
+	case TypeSetNickname:
+		var p SetNicknamePayload
+		if c.decodePayload(env, &p) {
+			c.handleSetNickname(p.Nickname)
+		}
+
+	case TypeReaction:
+		var p ReactionPayload
+		if c.decodePayload(env, &p) {
+			c.handleReaction(p.Reaction)
+		}
+
+	case TypeModeratorAuth:
+		var p ModeratorAuthPayload
+		if c.decodePayload(env, &p) {
+			c.handleModeratorAuth(p.Token)
+		}
+
+	case TypeModDelete:
+		var p ModDeletePayload
+		if c.decodePayload(env, &p) {
+			c.handleModDelete(p)
+		}
+
+	case TypeModBan:
+		var p ModBanPayload
+		if c.decodePayload(env, &p) {
+			c.handleModBan(p)
+		}
+
+	case TypeModUnban:
+		var p ModUnbanPayload
+		if c.decodePayload(env, &p) {
+			c.handleModUnban(p)
+		}
+
+	case TypeModSettings:
+		var p ModSettingsPayload
+		if c.decodePayload(env, &p) {
+			c.handleModSettings(p)
+		}
+
+	default:
+		c.sendError(ErrorUnknownType, fmt.Sprintf("Unknown message type %q", env.Type), env.Type)
+	}
+}
+
+// decodePayload unmarshals an envelope's payload, replying with an error
+// message if it is malformed. A missing payload leaves v at its zero value.
+func (c *Client) decodePayload(env Envelope, v any) bool {
+	if len(env.Payload) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(env.Payload, v); err != nil {
+		c.sendError(ErrorInvalidPayload, fmt.Sprintf("Invalid %s payload", env.Type), env.Type)
+		return false
+	}
+	return true
+}
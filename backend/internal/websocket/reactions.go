@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+type ReactionConfig struct {
+	// Allowed lists the reaction names viewers may send (e.g. "fire", "heart")
+	Allowed []string
+	// RateBurst and RateInterval limit reactions per client, like ChatConfig
+	RateBurst    int
+	RateInterval time.Duration
+	// OnMinuteTotals receives the reaction totals of each completed minute
+	OnMinuteTotals func(minute time.Time, counts map[string]int)
+}
+
+const (
+	// reactionTickInterval is how often aggregated reactions are broadcast
+	reactionTickInterval = time.Second
+	// hypeSmoothing weights the latest second in the hype meter's moving average
+	hypeSmoothing = 0.3
+)
+
+// reactionAggregator collects reactions between ticks. Only used from the run loop.
+type reactionAggregator struct {
+	second map[string]int
+	// remote holds reactions reported by other replicas since the last tick.
+	// They are broadcast but not persisted, the originating replica does that.
+	remote      map[string]int
+	minute      map[string]int
+	minuteStart time.Time
+	hype        float64
+}
+
+func newReactionAggregator(now time.Time) *reactionAggregator {
+	return &reactionAggregator{
+		second:      make(map[string]int),
+		remote:      make(map[string]int),
+		minute:      make(map[string]int),
+		minuteStart: now.Truncate(time.Minute),
+	}
+}
+
+func (c *Client) handleReaction(name string) {
+	if !slices.Contains(c.Manager.config.Reactions.Allowed, name) {
+		return
+	}
+
+	// Excess reactions are dropped silently; they carry no content worth an error
+	if !c.reactionLimiter.Allow(time.Now()) {
+		return
+	}
+
+	select {
+	case c.Manager.reactions <- name:
+	default:
+		// Run loop is saturated, drop the reaction
+	}
+}
+
+// tickReactions broadcasts the last second's reactions as a single event and
+// rolls minute totals over to OnMinuteTotals
+func (m *Manager) tickReactions(now time.Time) {
+	agg := m.reactionAgg
+
+	if len(agg.second) > 0 {
+		m.publish(ClusterEvent{Kind: ClusterReactions, Reactions: agg.second})
+	}
+
+	counts := agg.second
+	if len(agg.remote) > 0 {
+		counts = make(map[string]int, len(agg.second)+len(agg.remote))
+		for name, count := range agg.second {
+			counts[name] += count
+		}
+		for name, count := range agg.remote {
+			counts[name] += count
+		}
+		agg.remote = make(map[string]int)
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	previousHype := agg.hype
+	agg.hype = hypeSmoothing*float64(total) + (1-hypeSmoothing)*agg.hype
+	if agg.hype < 0.01 {
+		agg.hype = 0
+	}
+
+	// Stay quiet once the meter has settled at zero
+	if total > 0 || previousHype > 0 {
+		m.broadcastMessage(TypeReactions, ReactionsPayload{
+			Counts: counts,
+			Hype:   math.Round(agg.hype*100) / 100,
+		})
+	}
+
+	for name, count := range agg.second {
+		agg.minute[name] += count
+	}
+	agg.second = make(map[string]int)
+
+	if minute := now.Truncate(time.Minute); minute.After(agg.minuteStart) {
+		if len(agg.minute) > 0 && m.config.Reactions.OnMinuteTotals != nil {
+			// Persist outside the run loop so a slow database can't stall it
+			go m.config.Reactions.OnMinuteTotals(agg.minuteStart, agg.minute)
+		}
+		agg.minute = make(map[string]int)
+		agg.minuteStart = minute
+	}
+}
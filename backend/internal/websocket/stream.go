@@ -0,0 +1,83 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// recentSize is the number of broadcasts kept for event stream resumption
+const recentSize = 128
+
+// NewStreamClient creates a receive-only client for a Server-Sent Events
+// connection. It has no WebSocket; the caller drains Send and must
+// unregister the client when the request ends. lastEventID is the
+// Last-Event-ID sent by a reconnecting client ("" for a fresh stream).
+func NewStreamClient(manager *Manager, ip, viewerID, lastEventID string) *Client {
+	client := &Client{
+		ID:       uuid.New().String(),
+		IP:       ip,
+		ViewerID: viewerID,
+		Manager:  manager,
+		Send:     make(chan *Frame, sendBufferSize),
+		lastPing: time.Now(),
+		playing:  true,
+		nickname: defaultNickname(viewerID),
+	}
+	client.resumeAfter, client.resuming = manager.parseEventID(lastEventID)
+	return client
+}
+
+// EventID returns the event stream ID of a frame, or "" for messages that
+// are not part of the broadcast sequence. IDs carry the manager's boot ID so
+// that IDs from another process are not resumed.
+func (m *Manager) EventID(frame *Frame) string {
+	if frame.Seq == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d", m.bootID, frame.Seq)
+}
+
+func (m *Manager) parseEventID(id string) (uint64, bool) {
+	boot, seqStr, found := strings.Cut(id, "-")
+	if !found || boot != m.bootID {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// rememberBroadcast keeps an encoded broadcast for resumption. Only called from the run loop.
+func (m *Manager) rememberBroadcast(frame *Frame) {
+	if len(m.recent) >= recentSize {
+		copy(m.recent, m.recent[1:])
+		m.recent = m.recent[:len(m.recent)-1]
+	}
+	m.recent = append(m.recent, frame)
+}
+
+// resume replays the broadcasts a reconnecting client missed. It returns
+// false if some of them are no longer available, in which case the client
+// has to be caught up from scratch. Only called from the run loop.
+func (m *Manager) resume(client *Client) bool {
+	if !client.resuming {
+		return false
+	}
+	// The oldest kept message must directly follow the client's last one
+	if len(m.recent) == 0 || m.recent[0].Seq > client.resumeAfter+1 {
+		return client.resumeAfter == m.broadcastSeq
+	}
+
+	for _, frame := range m.recent {
+		if frame.Seq > client.resumeAfter && !client.trySend(frame) {
+			break
+		}
+	}
+	return true
+}
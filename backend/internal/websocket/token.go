@@ -0,0 +1,55 @@
+package websocket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TokenSigner issues and verifies anonymous viewer tokens.
+// A token is "<viewer id>.<HMAC-SHA256 of the id>", both base64url encoded,
+// so viewers can be recognised across tabs and reconnects without accounts.
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret []byte) *TokenSigner {
+	return &TokenSigner{secret: secret}
+}
+
+// Issue creates a new viewer identity and its signed token.
+// IDs are time-ordered (UUIDv7) so the identity's age can be derived.
+func (s *TokenSigner) Issue() (string, string) {
+	id := uuid.Must(uuid.NewV7()).String()
+	return id, s.sign(id)
+}
+
+// Verify returns the viewer ID embedded in a token if the signature is valid
+func (s *TokenSigner) Verify(token string) (string, bool) {
+	encodedID, _, found := strings.Cut(token, ".")
+	if !found {
+		return "", false
+	}
+
+	rawID, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil {
+		return "", false
+	}
+
+	id := string(rawID)
+	if !hmac.Equal([]byte(s.sign(id)), []byte(token)) {
+		return "", false
+	}
+	return id, true
+}
+
+func (s *TokenSigner) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(id)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -3,6 +3,7 @@ import { useState, useEffect } from 'react'
 import { Temporal } from 'temporal-polyfill'
 import { StreamViewer } from './pages/StreamViewer'
 import { Timetable } from './pages/Timetable'
+import { RecoverPasscode } from './pages/RecoverPasscode'
 import { EventTimezoneContext } from './hooks/useEventTimezone'
 import { configApi } from './api/client'
 import './App.css'
@@ -29,6 +30,7 @@ function App() {
             <Routes>
               <Route path="/" element={<StreamViewer />} />
               <Route path="/timetable" element={<Timetable />} />
+              <Route path="/recover-passcode" element={<RecoverPasscode />} />
             </Routes>
           </main>
         </div>
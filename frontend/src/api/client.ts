@@ -41,6 +41,10 @@ export const reservationsApi = {
     });
   },
 
+  resetPasscode: async (id: string, token: string, newPasscode: string): Promise<void> => {
+    await apiClient.put(`/reservations/${id}/passcode`, { token, newPasscode });
+  },
+
   getAvailableSlots: async (startTime: Temporal.Instant, endTime?: Temporal.Instant): Promise<TimeSlot[]> => {
     const params: { startTime: string; endTime?: string } = { startTime: startTime.toString() };
     if (endTime) {
@@ -4,9 +4,11 @@ import Hls from 'hls.js';
 interface HLSPlayerProps {
   src: string;
   autoPlay?: boolean;
+  // Called when playback starts, pauses or ends, and with false on unmount
+  onPlayingChange?: (playing: boolean) => void;
 }
 
-export function HLSPlayer({ src, autoPlay = true }: HLSPlayerProps) {
+export function HLSPlayer({ src, autoPlay = true, onPlayingChange }: HLSPlayerProps) {
   const videoRef = useRef<HTMLVideoElement>(null);
   const hlsRef = useRef<Hls | null>(null);
   const [showPlayButton, setShowPlayButton] = useState(false);
@@ -99,6 +101,24 @@ export function HLSPlayer({ src, autoPlay = true }: HLSPlayerProps) {
     };
   }, [src, autoPlay]);
 
+  useEffect(() => {
+    const video = videoRef.current;
+    if (!video || !onPlayingChange) return;
+
+    const handlePlaying = () => onPlayingChange(true);
+    const handleStopped = () => onPlayingChange(false);
+    video.addEventListener('playing', handlePlaying);
+    video.addEventListener('pause', handleStopped);
+    video.addEventListener('ended', handleStopped);
+
+    return () => {
+      video.removeEventListener('playing', handlePlaying);
+      video.removeEventListener('pause', handleStopped);
+      video.removeEventListener('ended', handleStopped);
+      onPlayingChange(false);
+    };
+  }, [onPlayingChange]);
+
   const handlePlayClick = () => {
     if (videoRef.current) {
       videoRef.current.play();
@@ -1,4 +1,11 @@
-import { useEffect, useState, useRef } from 'react';
+import { useCallback, useEffect, useState, useRef } from 'react';
+import { WS_PROTOCOL_VERSION } from '../types/api';
+import type { PlayerStatePayload, ViewerCountPayload, WsEnvelope } from '../types/api';
+
+const sendPlayerState = (ws: WebSocket, playing: boolean) => {
+  const payload: PlayerStatePayload = { playing };
+  ws.send(JSON.stringify({ type: 'player_state', version: WS_PROTOCOL_VERSION, payload }));
+};
 
 export function useViewerCount() {
   const [viewerCount, setViewerCount] = useState<number | null>(null);
@@ -6,6 +13,9 @@ export function useViewerCount() {
   const wsRef = useRef<WebSocket | null>(null);
   const reconnectTimeoutRef = useRef<number | null>(null);
   const reconnectAttempts = useRef(0);
+  // The server counts a connection as a viewer until told otherwise, so the
+  // player state is reported on every (re)connect and whenever it changes
+  const playingRef = useRef(false);
 
   useEffect(() => {
     const connect = () => {
@@ -22,17 +32,18 @@ export function useViewerCount() {
           console.log('WebSocket connected');
           setIsConnected(true);
           reconnectAttempts.current = 0;
+          sendPlayerState(ws, playingRef.current);
         };
 
         ws.onmessage = (event) => {
           try {
-            const data = JSON.parse(event.data);
+            const message: WsEnvelope = JSON.parse(event.data);
             
-            if (data.type === 'viewer_count') {
-              setViewerCount(data.count);
-            } else if (data.type === 'ping') {
+            if (message.type === 'viewer_count') {
+              setViewerCount((message.payload as ViewerCountPayload).count);
+            } else if (message.type === 'ping') {
               // Send pong response
-              ws.send(JSON.stringify({ type: 'pong' }));
+              ws.send(JSON.stringify({ type: 'pong', version: WS_PROTOCOL_VERSION }));
             }
           } catch (error) {
             console.error('Error parsing WebSocket message:', error);
@@ -74,5 +85,14 @@ export function useViewerCount() {
     };
   }, []);
 
-  return { viewerCount, isConnected };
+  const reportPlayerState = useCallback((playing: boolean) => {
+    if (playingRef.current === playing) return;
+    playingRef.current = playing;
+    const ws = wsRef.current;
+    if (ws && ws.readyState === WebSocket.OPEN) {
+      sendPlayerState(ws, playing);
+    }
+  }, []);
+
+  return { viewerCount, isConnected, reportPlayerState };
 }
\ No newline at end of file
@@ -0,0 +1,103 @@
+import { useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import axios from 'axios';
+import { reservationsApi } from '../api/client';
+
+// RecoverPasscode is opened from the link in the passcode recovery mail
+export function RecoverPasscode() {
+  const [searchParams] = useSearchParams();
+  const reservationId = searchParams.get('reservationId') ?? '';
+  const token = searchParams.get('token') ?? '';
+
+  const [passcode, setPasscode] = useState('');
+  const [confirmPasscode, setConfirmPasscode] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState('');
+  const [done, setDone] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+
+    if (passcode !== confirmPasscode) {
+      setError('確認用の暗証番号が一致しません');
+      return;
+    }
+
+    setLoading(true);
+    try {
+      await reservationsApi.resetPasscode(reservationId, token, passcode);
+      setDone(true);
+    } catch (err: unknown) {
+      if (axios.isAxiosError(err) && err.response?.status === 401) {
+        setError('再設定リンクが無効か期限切れです。もう一度再設定をリクエストしてください');
+      } else if (axios.isAxiosError(err) && err.response?.data?.code === 'INVALID_PASSCODE') {
+        setError(err.response.data.message || '暗証番号の形式が正しくありません');
+      } else {
+        setError('暗証番号の再設定に失敗しました');
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (!reservationId || !token) {
+    return (
+      <div className="recover-passcode">
+        <h2>暗証番号の再設定</h2>
+        <div className="error-message">再設定リンクが正しくありません。メールのリンクをそのまま開いてください</div>
+      </div>
+    );
+  }
+
+  if (done) {
+    return (
+      <div className="recover-passcode">
+        <h2>暗証番号の再設定</h2>
+        <p>新しい暗証番号を設定しました。</p>
+        <Link to="/timetable" className="nav-link">タイムテーブルへ</Link>
+      </div>
+    );
+  }
+
+  return (
+    <div className="recover-passcode">
+      <h2>暗証番号の再設定</h2>
+
+      <form onSubmit={handleSubmit}>
+        <div className="form-group">
+          <label htmlFor="new-passcode">新しい暗証番号</label>
+          <input
+            id="new-passcode"
+            type="password"
+            value={passcode}
+            onChange={(e) => setPasscode(e.target.value)}
+            autoComplete="new-password"
+            required
+            autoFocus
+          />
+        </div>
+
+        <div className="form-group">
+          <label htmlFor="confirm-passcode">新しい暗証番号（確認）</label>
+          <input
+            id="confirm-passcode"
+            type="password"
+            value={confirmPasscode}
+            onChange={(e) => setConfirmPasscode(e.target.value)}
+            autoComplete="new-password"
+            required
+          />
+        </div>
+
+        {error && <div className="error-message">{error}</div>}
+
+        <div className="form-actions">
+          <button type="submit" disabled={loading || !passcode || !confirmPasscode}>
+            {loading ? '設定中...' : '再設定する'}
+          </button>
+        </div>
+      </form>
+    </div>
+  );
+}
@@ -9,7 +9,7 @@ const HLS_ENDPOINT = import.meta.env.VITE_HLS_ENDPOINT || 'http://localhost:8888
 export function StreamViewer() {
   const timezone = useEventTimezone();
   const { status, loading, error } = useStreamStatus();
-  const { viewerCount } = useViewerCount();
+  const { viewerCount, reportPlayerState } = useViewerCount();
 
   if (loading) {
     return (
@@ -126,7 +126,12 @@ export function StreamViewer() {
 
       <div className="player-container">
         {status.isLive ? (
-          <HLSPlayer src={HLS_ENDPOINT} />
+          <HLSPlayer src={HLS_ENDPOINT} onPlayingChange={reportPlayerState} />
+        ) : status.state === 'fallback' && status.playbackPath ? (
+          <>
+            <HLSPlayer src={status.playbackPath} onPlayingChange={reportPlayerState} />
+            <p className="fallback-message">まもなく配信を再開します</p>
+          </>
         ) : (
           <div className="offline-message">
             <p>現在配信はオフラインです</p>
@@ -1,7 +1,11 @@
 import { Temporal } from "temporal-polyfill";
 
+export type StreamState = 'live' | 'fallback' | 'offline';
+
 export interface StreamStatus {
   isLive: boolean;
+  state: StreamState;
+  playbackPath?: string;
   currentDj?: string;
   nextDj?: string;
   currentStartTime?: Temporal.Instant;
@@ -11,6 +15,8 @@ export interface StreamStatus {
 
 export interface StreamStatusResponse {
   isLive: boolean;
+  state?: StreamState;
+  playbackPath?: string;
   currentDj?: string;
   nextDj?: string;
   currentStartTime?: string;
@@ -21,6 +27,8 @@ export interface StreamStatusResponse {
 export const buildStreamStatus = (plainData: StreamStatusResponse): StreamStatus => {
   return {
     isLive: plainData.isLive,
+    state: plainData.state ?? (plainData.isLive ? 'live' : 'offline'),
+    playbackPath: plainData.playbackPath,
     currentDj: plainData.currentDj,
     nextDj: plainData.nextDj,
     currentStartTime: plainData.currentStartTime ? Temporal.Instant.from(plainData.currentStartTime) : undefined,
@@ -119,4 +127,23 @@ export const buildEventConfig = (plainData: EventConfigResponse): EventConfig =>
     eventEndTime: plainData.eventEndTime ? Temporal.Instant.from(plainData.eventEndTime) : undefined,
     timezone: plainData.timezone,
   };
-};
\ No newline at end of file
+};
+
+// Viewer WebSocket protocol (see api/asyncapi.yaml)
+export const WS_PROTOCOL_VERSION = 1;
+
+export interface WsEnvelope {
+  type: string;
+  version: number;
+  seq?: number;
+  payload?: unknown;
+}
+
+export interface ViewerCountPayload {
+  count: number;
+  connections: number;
+}
+
+export interface PlayerStatePayload {
+  playing: boolean;
+}